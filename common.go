@@ -1,5 +1,9 @@
 package somesql
 
+import (
+	uuid "github.com/satori/go.uuid"
+)
+
 // getFieldValueFunctions is a helper function to get field and value funcs for sql from a list of 0 or more args
 func getFieldValueFunctions(funcs []string) (string, string) {
 	if l := len(funcs); l == 0 {
@@ -31,6 +35,19 @@ func expandValues(val interface{}) ([]interface{}, bool) {
 			values = append(values, data[d])
 		}
 		asserted = true
+	} else if data, ok := val.([]int64); ok {
+		for d := range data {
+			values = append(values, data[d])
+		}
+		asserted = true
+	} else if data, ok := val.([]uuid.UUID); ok {
+		for d := range data {
+			values = append(values, data[d])
+		}
+		asserted = true
+	} else if data, ok := val.([]interface{}); ok {
+		values = append(values, data...)
+		asserted = true
 	} else {
 		return []interface{}{val}, false
 	}
@@ -38,6 +55,17 @@ func expandValues(val interface{}) ([]interface{}, bool) {
 	return values, asserted
 }
 
+// asSlice returns val as a slice of values, wrapping single values
+func asSlice(val interface{}) []interface{} {
+	if values, ok := val.([]interface{}); ok {
+		return values
+	}
+
+	values, _ := expandValues(val)
+
+	return values
+}
+
 // getSliceChange returns all elements that are present in sliceTwo but NOT in sliceOne
 // it can be used for several purposes. for example if we have 2 slices:
 // - s1 [a, b, c]
@@ -1,10 +1,42 @@
 package somesql
 
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
 var (
 	and = andor(AndCondition)
 	or  = andor(OrCondition)
 )
 
+// Operators constants
+const (
+	OpEqual        string = "="
+	OpNotEqual     string = "<>"
+	OpLess         string = "<"
+	OpLessEqual    string = "<="
+	OpGreater      string = ">"
+	OpGreaterEqual string = ">="
+	OpLike         string = "LIKE"
+	OpNotLike      string = "NOT LIKE"
+	OpILike        string = "ILIKE"
+	OpNotILike     string = "NOT ILIKE"
+	OpBetween      string = "BETWEEN"
+	OpNotBetween   string = "NOT BETWEEN"
+	OpIsNull       string = "IS NULL"
+	OpIsNotNull    string = "IS NOT NULL"
+	OpHasKey       string = "?"
+	OpHasAnyKey    string = "?|"
+	OpHasAllKeys   string = "?&"
+	OpContains     string = "@>"
+	OpMatch        string = "~"
+	OpIMatch       string = "~*"
+	OpNotMatch     string = "!~"
+	OpNotIMatch    string = "!~*"
+)
+
 // ConditionClause represents a single conditional clause
 type ConditionClause struct {
 	Type          uint8
@@ -14,6 +46,7 @@ type ConditionClause struct {
 	ValueFunction string
 	Value         interface{}
 	Lang          string
+	ctx           conditionContext
 }
 
 // andor is a factory function
@@ -52,32 +85,125 @@ func (c ConditionClause) ConditionType() uint8 {
 	return c.Type
 }
 
+// withContext to satisfy interface contextCondition
+func (c ConditionClause) withContext(ctx conditionContext) Condition {
+	c.ctx = ctx
+	return c
+}
+
+// check to satisfy interface checkedCondition
+// the number of values depends on the operator, relations being matched against their elements
+func (c ConditionClause) check(table Table) []error {
+	var (
+		errs     = checkLangs(table, c.Lang)
+		operator = normalizeOperator(c.Operator)
+	)
+
+	if err := checkField(table, c.Field); err != nil {
+		errs = append(errs, err)
+	}
+
+	errs = appendError(errs, c.checkOperator(table, operator))
+	errs = appendError(errs, checkFunction(c.Field, c.FieldFunction, c.ctx.isHaving()))
+	errs = appendError(errs, checkFunction(c.Field, c.ValueFunction, false))
+
+	if ref, ok := c.Value.(Reference); ok {
+		if !isComparison(operator) {
+			return append(errs, FieldError{Field: c.Field, Err: fmt.Errorf("%w: reference with %s", ErrUnsupportedValue, c.Operator)})
+		}
+		return appendError(errs, ref.check())
+	}
+
+	vals := c.values(operator)
+
+	switch operator {
+	case OpIsNull, OpIsNotNull:
+		return errs // the value is ignored
+	case OpContains:
+		if _, err := json.Marshal(c.Value); err != nil {
+			return append(errs, FieldError{Field: c.Field, Err: fmt.Errorf("%w: %v", ErrInvalidJSON, err)})
+		}
+		return errs
+	case OpHasAnyKey, OpHasAllKeys:
+		if len(vals) == 0 {
+			return append(errs, FieldError{Field: c.Field, Err: ErrNoValues})
+		}
+	case OpBetween, OpNotBetween:
+		if len(vals) != 2 {
+			return append(errs, FieldError{Field: c.Field, Err: fmt.Errorf("%w %T", ErrUnsupportedValue, c.Value)})
+		}
+	default:
+		if len(vals) != 1 {
+			return append(errs, FieldError{Field: c.Field, Err: fmt.Errorf("%w %T", ErrUnsupportedValue, c.Value)})
+		}
+	}
+
+	return append(errs, checkValues(c.Field, vals)...)
+}
+
+// checkOperator returns ErrInvalidOperator if operator cannot apply to the field
+// key existence and containment apply to JSON, the operator of relations is membership
+func (c ConditionClause) checkOperator(table Table, operator string) error {
+	_, isRelation := GetInnerField(FieldRelations, c.Field)
+
+	switch operator {
+	case OpHasKey, OpHasAnyKey, OpHasAllKeys, OpContains:
+		if !strings.Contains(c.Field, ".") && !table.IsJSONB(c.Field) {
+			return FieldError{Field: c.Field, Err: fmt.Errorf("%w: %s on a field which is not JSON", ErrInvalidOperator, c.Operator)}
+		}
+		return nil
+	case OpIsNull, OpIsNotNull:
+		return nil
+	case OpBetween, OpNotBetween, OpMatch, OpIMatch, OpNotMatch, OpNotIMatch:
+		if isRelation {
+			return FieldError{Field: c.Field, Err: fmt.Errorf("%w: %s on relations", ErrInvalidOperator, c.Operator)}
+		}
+	}
+
+	if isRelation {
+		return nil
+	}
+
+	return checkOperator(c.Field, c.Operator)
+}
+
 // AsSQL to satisfy interface Condition
 func (c ConditionClause) AsSQL(in ...bool) (string, []interface{}) {
 	var (
 		lhs, rhs, field string
-		isInnerRel      bool
-		dataFieldLang   string
+		d               = c.ctx.getDialect()
+		operator        = normalizeOperator(c.Operator)
+		vals            = c.values(operator)
 	)
 
-	dataFieldLang = GetLangFieldData(c.Lang)
+	switch operator {
+	case OpHasKey, OpHasAnyKey, OpHasAllKeys:
+		return d.JSONHasKeys(c.jsonField(d), operator, len(vals)), vals
+	case OpContains:
+		return d.JSONContains(c.jsonField(d)), vals
+	}
 
-	if IsFieldMeta(c.Field) || IsFieldData(c.Field) || IsFieldRelations(c.Field) {
-		if IsFieldData(c.Field) || IsFieldRelations(c.Field) {
-			field = `"` + dataFieldLang + `"`
-		} else {
-			field = `"` + c.Field + `"`
-		}
+	if expr, ok := c.ctx.aggregates[c.Field]; ok {
+		field = expr
+	} else if !strings.Contains(c.Field, ".") {
+		field = getFallbackColumn(d, c.Field, c.Lang, c.ctx.fallback)
 	} else if innerField, ok := GetInnerField(FieldData, c.Field); ok {
-		field = `"` + dataFieldLang + `"->>'` + innerField + `'`
+		field = getFallbackAccessor(d, FieldData, c.Lang, c.ctx.fallback, innerField, true)
 	} else if innerField, ok := GetInnerField(FieldRelations, c.Field); ok {
-		field = `jsonb_path_exists("` + dataFieldLang + `", '$.` + innerField + `[*] £ (@ `
-		c.Operator = "=="
-		rhs = `$val)', json_object(ARRAY['val', ?])::jsonb)`
-		isInnerRel = true
+		if operator != OpIsNull && operator != OpIsNotNull {
+			// relations are arrays, the value is matched against their elements
+			value := "?"
+			if ref, ok := c.Value.(Reference); ok {
+				value = d.Cast(ref.sql(d, c.Lang), "TEXT")
+			}
+			return "(" + d.JSONArrayHas(getFallbackColumn(d, FieldRelations, c.Lang, c.ctx.fallback), innerField, value) + ")", vals
+		}
+		field = getFallbackAccessor(d, FieldRelations, c.Lang, c.ctx.fallback, innerField, true)
+	} else if parent, innerField, ok := getInnerFieldAny(c.Field); ok {
+		field = d.JSONAccessor(quoteIdentifier(parent), innerField, true)
 	}
 
-	if c.FieldFunction == None || isInnerRel {
+	if c.FieldFunction == None {
 		lhs = field
 	} else {
 		lhs = c.FieldFunction + "(" + field + ")"
@@ -85,22 +211,93 @@ func (c ConditionClause) AsSQL(in ...bool) (string, []interface{}) {
 
 	switch c.Value.(type) {
 	case bool:
-		lhs = "(" + lhs + ")::BOOLEAN"
+		lhs = d.Cast("("+lhs+")", "BOOLEAN")
 	}
 
-	if isInnerRel {
-		// Do nothing
-	} else if c.ValueFunction == None {
-		rhs = "?"
-	} else {
-		rhs = c.ValueFunction + "(?)"
+	rhs = "?"
+	if ref, ok := c.Value.(Reference); ok {
+		rhs = ref.sql(d, c.Lang)
+	}
+
+	if c.ValueFunction != None {
+		rhs = c.ValueFunction + "(" + rhs + ")"
+	}
+
+	switch operator {
+	case OpIsNull, OpIsNotNull:
+		return lhs + " " + operator, vals
+	case OpBetween, OpNotBetween:
+		if strings.Contains(c.Field, ".") && isNumeric(vals) { // inner fields are compared as numbers rather than text
+			lhs = d.Cast("("+lhs+")", "NUMERIC")
+		}
+		rhs = rhs + " AND " + rhs
+	}
+
+	return d.Compare(lhs, c.Operator, rhs), vals
+}
+
+// values returns the values of the condition for operator, as sent as parameters
+// the value of containment is sent as a JSON document, references are not sent
+func (c ConditionClause) values(operator string) []interface{} {
+	if _, ok := c.Value.(Reference); ok {
+		return []interface{}{}
+	}
+
+	switch operator {
+	case OpIsNull, OpIsNotNull:
+		return []interface{}{}
+	case OpBetween, OpNotBetween, OpHasAnyKey, OpHasAllKeys:
+		return asSlice(c.Value)
+	case OpContains:
+		jsonBytes, err := json.Marshal(c.Value)
+		if err != nil {
+			return []interface{}{c.Value}
+		}
+		return []interface{}{string(jsonBytes)}
 	}
 
 	vals, _ := expandValues(c.Value)
 
-	if isInnerRel {
-		return "(" + lhs + " " + c.Operator + " " + rhs + ")", vals
+	return vals
+}
+
+// jsonField returns the SQL of the field as JSON, for key existence and containment
+func (c ConditionClause) jsonField(d Dialect) string {
+	if innerField, ok := GetInnerField(FieldData, c.Field); ok {
+		return getFallbackAccessor(d, FieldData, c.Lang, c.ctx.fallback, innerField, false)
+	} else if innerField, ok := GetInnerField(FieldRelations, c.Field); ok {
+		return getFallbackAccessor(d, FieldRelations, c.Lang, c.ctx.fallback, innerField, false)
+	} else if parent, innerField, ok := getInnerFieldAny(c.Field); ok {
+		return d.JSONAccessor(quoteIdentifier(parent), innerField, false)
+	}
+
+	return getFallbackColumn(d, c.Field, c.Lang, c.ctx.fallback)
+}
+
+// normalizeOperator returns operator in upper case without surrounding spaces
+func normalizeOperator(operator string) string {
+	return strings.ToUpper(strings.TrimSpace(operator))
+}
+
+// isComparison returns true if operator compares a single value i.e = or LIKE
+func isComparison(operator string) bool {
+	switch operator {
+	case OpBetween, OpNotBetween, OpIsNull, OpIsNotNull, OpHasKey, OpHasAnyKey, OpHasAllKeys, OpContains:
+		return false
+	}
+
+	return true
+}
+
+// isNumeric returns true if values are all numbers
+func isNumeric(values []interface{}) bool {
+	for _, v := range values {
+		switch v.(type) {
+		case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32, float64:
+		default:
+			return false
+		}
 	}
 
-	return lhs + " " + c.Operator + " " + rhs, vals
+	return len(values) > 0
 }
@@ -288,6 +288,31 @@ func TestConditionClause(t *testing.T) {
 			[]interface{}{"John Doe"},
 			caseOr,
 		},
+		{
+			"AND JSONB nested",
+			args{
+				lang:     "fr",
+				field:    "data.seo.title",
+				operator: "=",
+				value:    "abc",
+				funcs:    []string{"LOWER"},
+			},
+			`LOWER("data_fr"#>>'{seo,title}') = ?`,
+			[]interface{}{"abc"},
+			caseAnd,
+		},
+		{
+			"OR JSONB nested (boolean)",
+			args{
+				lang:     "en",
+				field:    "data.image.meta.has_caption",
+				operator: "=",
+				value:    true,
+			},
+			`("data_en"#>>'{image,meta,has_caption}')::BOOLEAN = ?`,
+			[]interface{}{true},
+			caseOr,
+		},
 	}
 
 	for i, tt := range tests {
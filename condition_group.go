@@ -1,11 +1,15 @@
 package somesql
 
-import "strings"
+import (
+	"fmt"
+	"strings"
+)
 
 //ConditionGroup represents a group of condition (within same pair brackets)
 type ConditionGroup struct {
 	Type       uint8
 	Conditions []Condition
+	Not        bool
 }
 
 //AndGroup creates a condition group in the format AND((condition1) OR (condition2) AND (condition3))
@@ -24,11 +28,45 @@ func OrGroup(conditions ...Condition) ConditionGroup {
 	}
 }
 
+//AndNot creates a negated condition group in the format AND NOT((condition1) OR (condition2) AND (condition3))
+//a single condition is negated with AndNot(condition)
+func AndNot(conditions ...Condition) ConditionGroup {
+	return ConditionGroup{
+		Type:       AndCondition,
+		Conditions: conditions,
+		Not:        true,
+	}
+}
+
+//OrNot creates a negated condition group in the format OR NOT((condition1) OR (condition2) AND (condition3))
+func OrNot(conditions ...Condition) ConditionGroup {
+	return ConditionGroup{
+		Type:       OrCondition,
+		Conditions: conditions,
+		Not:        true,
+	}
+}
+
 //ConditionType to satisfy interface Condition
 func (c ConditionGroup) ConditionType() uint8 {
 	return c.Type
 }
 
+//withContext to satisfy interface contextCondition
+func (c ConditionGroup) withContext(ctx conditionContext) Condition {
+	c.Conditions = withContext(c.Conditions, ctx)
+	return c
+}
+
+//check to satisfy interface checkedCondition
+func (c ConditionGroup) check(table Table) []error {
+	if len(c.Conditions) == 0 {
+		return []error{fmt.Errorf("%w: condition group", ErrEmptyClause)}
+	}
+
+	return checkConditions(c.Conditions, table)
+}
+
 //AsSQL to satisfy interface Condition
 func (c ConditionGroup) AsSQL(in ...bool) (string, []interface{}) {
 	var (
@@ -51,6 +89,10 @@ func (c ConditionGroup) AsSQL(in ...bool) (string, []interface{}) {
 
 	if sqlBuff.Len() > 0 {
 		sqlStr = "(" + sqlBuff.String() + ")"
+
+		if c.Not {
+			sqlStr = "NOT " + sqlStr
+		}
 	}
 
 	return sqlStr, values
@@ -1,9 +1,11 @@
 package somesql_test
 
 import (
+	"errors"
 	"fmt"
 	"testing"
 
+	"github.com/lib/pq"
 	"github.com/stretchr/testify/assert"
 	"go.lsl.digital/lardwaz/somesql"
 )
@@ -135,3 +137,71 @@ func TestConditionGroup(t *testing.T) {
 		})
 	}
 }
+
+func TestConditionGroup_Not(t *testing.T) {
+	type testcase struct {
+		name      string
+		condition somesql.Condition
+		sql       string
+		values    []interface{}
+		caseType  uint8
+	}
+
+	tests := []testcase{
+		{
+			"AND NOT",
+			somesql.AndNot(
+				somesql.And("en", "type", "=", "article"),
+				somesql.AndContains("en", "relations.author", []string{"u1"}),
+			),
+			`NOT ("type" = ? AND "data_en"->'author' @> ?::JSONB)`,
+			[]interface{}{"article", `["u1"]`},
+			somesql.AndCondition,
+		},
+		{
+			"OR NOT (single condition)",
+			somesql.OrNot(somesql.AndIn("en", "id", []string{"a", "b"})),
+			`NOT ("id" = ANY(?))`,
+			[]interface{}{pq.Array([]interface{}{"a", "b"})},
+			somesql.OrCondition,
+		},
+		{
+			"NOT within group",
+			somesql.AndGroup(
+				somesql.And("en", "type", "=", "article"),
+				somesql.OrNot(
+					somesql.And("en", "data.title", "=", "x"),
+					somesql.OrGroup(somesql.AndIsNull("en", "owner_id")),
+				),
+			),
+			`("type" = ? OR NOT ("data_en"->>'title' = ? OR ("owner_id" IS NULL)))`,
+			[]interface{}{"article", "x"},
+			somesql.AndCondition,
+		},
+		{
+			"NOT of NOT",
+			somesql.AndNot(somesql.AndNot(somesql.And("en", "id", "=", "a"))),
+			`NOT (NOT ("id" = ?))`,
+			[]interface{}{"a"},
+			somesql.AndCondition,
+		},
+	}
+
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sql, values := tt.condition.AsSQL()
+
+			assert.Equal(t, tt.sql, sql, fmt.Sprintf("%d: SQL invalid", i+1))
+			assert.Equal(t, tt.values, values, fmt.Sprintf("%d: Values invalid", i+1))
+			assert.Equal(t, tt.caseType, tt.condition.ConditionType(), fmt.Sprintf("%d: Condition type invalid", i+1))
+		})
+	}
+
+	sql, values, err := somesql.NewDelete("en").Where(somesql.AndNot(somesql.And("en", "type", "=", "article"))).Where(somesql.OrNot(somesql.And("en", "id", "=", "a"), somesql.Or("en", "id", "=", "b"))).Build()
+	assert.Nil(t, err)
+	assert.Equal(t, `DELETE FROM repo WHERE NOT ("type" = $1) OR NOT ("id" = $2 OR "id" = $3)`, sql, "NOT groups within WHERE")
+	assert.Equal(t, []interface{}{"article", "a", "b"}, values)
+
+	_, _, err = somesql.NewSelect("en").Where(somesql.AndNot()).Build()
+	assert.True(t, errors.Is(err, somesql.ErrEmptyClause), "empty NOT group")
+}
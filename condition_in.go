@@ -1,6 +1,7 @@
 package somesql
 
 import (
+	"fmt"
 	"strings"
 )
 
@@ -12,6 +13,7 @@ var (
 )
 
 // ConditionIn represents a condition in the format IN(?,?,?) / NOT IN(?,?,?)
+// values are bound as a single array where the dialect permits i.e = ANY(?) / <> ALL(?)
 type ConditionIn struct {
 	Type          uint8
 	Field         string
@@ -19,6 +21,7 @@ type ConditionIn struct {
 	Operator      string
 	Values        interface{}
 	Lang          string
+	ctx           conditionContext
 }
 
 // andOrIn is a factory function
@@ -64,23 +67,51 @@ func (c ConditionIn) ConditionType() uint8 {
 	return c.Type
 }
 
+// withContext to satisfy interface contextCondition
+func (c ConditionIn) withContext(ctx conditionContext) Condition {
+	c.ctx = ctx
+	return c
+}
+
+// check to satisfy interface checkedCondition
+// functions are not applied to the elements of inner fields, ErrInvalidFunction is reported for these
+func (c ConditionIn) check(table Table) []error {
+	errs := checkLangs(table, c.Lang)
+
+	if err := checkField(table, c.Field); err != nil {
+		errs = append(errs, err)
+	}
+
+	if c.FieldFunction != None && strings.Contains(c.Field, ".") {
+		errs = append(errs, FieldError{Field: c.Field, Err: fmt.Errorf("%w: %q on inner field", ErrInvalidFunction, c.FieldFunction)})
+	} else {
+		errs = appendError(errs, checkFunction(c.Field, c.FieldFunction, c.ctx.isHaving()))
+	}
+
+	vals, _ := expandValues(c.Values)
+	if len(vals) == 0 {
+		return append(errs, FieldError{Field: c.Field, Err: ErrNoValues})
+	}
+
+	return append(errs, checkValues(c.Field, vals)...)
+}
+
 // AsSQL to satisfy interface Condition
 func (c ConditionIn) AsSQL(in ...bool) (string, []interface{}) {
 	var (
-		lhs, rhs, field         string
-		isInnerData, isInnerRel bool
-		vals                    []interface{}
-		dataFieldLang           string
-
-		rhsBuff strings.Builder
+		lhs, field         string
+		column, innerField string
+		vals               []interface{}
+		d                  = c.ctx.getDialect()
 	)
 
-	dataFieldLang = GetLangFieldData(c.Lang)
-
 	vals, _ = expandValues(c.Values)
 
-	if IsFieldMeta(c.Field) || IsFieldData(c.Field) || IsFieldRelations(c.Field) {
-		field = `"` + c.Field + `"`
+	if !strings.Contains(c.Field, ".") {
+		field = quoteIdentifier(c.Field)
+		if expr, ok := c.ctx.aggregates[c.Field]; ok {
+			field = expr
+		}
 
 		if c.FieldFunction == None {
 			lhs = field
@@ -88,36 +119,25 @@ func (c ConditionIn) AsSQL(in ...bool) (string, []interface{}) {
 			lhs = c.FieldFunction + "(" + field + ")"
 		}
 
-		lhs = lhs + " " + c.Operator
-	} else if innerField, ok := GetInnerField(FieldData, c.Field); ok {
-		field = `"` + innerField + `"`
-		lhs = `("` + dataFieldLang + `" @> `
-		isInnerData = true
-	} else if innerField, ok := GetInnerField(FieldRelations, c.Field); ok {
-		field = `"` + innerField + `"`
-		lhs = `("` + dataFieldLang + `" @> `
-		isInnerRel = true
-	}
+		list, vals := d.ArrayParam(vals)
 
-	for range vals {
-		if isInnerData || isInnerRel {
-			rhsBuff.WriteString(`'{` + field + `:["?"]}'::JSONB OR `)
-		} else {
-			rhsBuff.WriteString(`?,`)
-		}
+		return d.In(lhs, list, c.Operator == "NOT IN"), vals
 	}
 
-	if rhsBuff.Len() > 0 {
-		if isInnerData || isInnerRel {
-			rhs = rhsBuff.String()[:rhsBuff.Len()-4] + ")" // trim " OR "
-		} else {
-			rhs = " (" + rhsBuff.String()[:rhsBuff.Len()-1] + ")" // trim ","
-		}
+	if inner, ok := GetInnerField(FieldData, c.Field); ok {
+		column, innerField = getFallbackColumn(d, FieldData, c.Lang, c.ctx.fallback), inner
+	} else if inner, ok := GetInnerField(FieldRelations, c.Field); ok {
+		column, innerField = getFallbackColumn(d, FieldRelations, c.Lang, c.ctx.fallback), inner
+	} else if parent, inner, ok := getInnerFieldAny(c.Field); ok {
+		column, innerField = quoteIdentifier(parent), inner
 	}
 
-	if c.Operator == "NOT IN" && (isInnerData || isInnerRel) {
-		return "NOT" + lhs + rhs, vals
+	list, vals := d.ArrayParam(vals)
+
+	sql := d.JSONArrayHasAny(column, innerField, list)
+	if c.Operator == "NOT IN" {
+		return "NOT " + sql, vals
 	}
 
-	return lhs + rhs, vals
+	return sql, vals
 }
@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"testing"
 
+	"github.com/lib/pq"
+	uuid "github.com/satori/go.uuid"
 	"github.com/stretchr/testify/assert"
 	"go.lsl.digital/lardwaz/somesql"
 )
@@ -39,8 +41,8 @@ func TestConditionIn(t *testing.T) {
 				field: "id",
 				value: []string{"A", "B", "C"},
 			},
-			`"id" IN (?,?,?)`,
-			[]interface{}{"A", "B", "C"},
+			`"id" = ANY(?)`,
+			[]interface{}{pq.Array([]interface{}{"A", "B", "C"})},
 			caseAndIn,
 		},
 		{
@@ -50,8 +52,8 @@ func TestConditionIn(t *testing.T) {
 				field: "type",
 				value: []bool{true, false},
 			},
-			`"type" IN (?,?)`,
-			[]interface{}{true, false},
+			`"type" = ANY(?)`,
+			[]interface{}{pq.Array([]interface{}{true, false})},
 			caseAndIn,
 		},
 		{
@@ -61,8 +63,41 @@ func TestConditionIn(t *testing.T) {
 				field: "type",
 				value: []int{1, 2},
 			},
-			`"type" IN (?,?)`,
-			[]interface{}{1, 2},
+			`"type" = ANY(?)`,
+			[]interface{}{pq.Array([]interface{}{1, 2})},
+			caseAndIn,
+		},
+		{
+			"AND IN (int64 values)",
+			args{
+				lang:  "en",
+				field: "type",
+				value: []int64{1, 2},
+			},
+			`"type" = ANY(?)`,
+			[]interface{}{pq.Array([]interface{}{int64(1), int64(2)})},
+			caseAndIn,
+		},
+		{
+			"AND IN (uuid values)",
+			args{
+				lang:  "en",
+				field: "id",
+				value: []uuid.UUID{uuid.Nil},
+			},
+			`"id" = ANY(?)`,
+			[]interface{}{pq.Array([]interface{}{uuid.Nil})},
+			caseAndIn,
+		},
+		{
+			"AND IN (interface values)",
+			args{
+				lang:  "en",
+				field: "owner_id",
+				value: []interface{}{"A", 1},
+			},
+			`"owner_id" = ANY(?)`,
+			[]interface{}{pq.Array([]interface{}{"A", 1})},
 			caseAndIn,
 		},
 		{
@@ -73,8 +108,8 @@ func TestConditionIn(t *testing.T) {
 				value: []string{"2019"},
 				funcs: []string{"YEAR"},
 			},
-			`YEAR("updated_at") IN (?)`,
-			[]interface{}{"2019"},
+			`YEAR("updated_at") = ANY(?)`,
+			[]interface{}{pq.Array([]interface{}{"2019"})},
 			caseAndIn,
 		},
 		{
@@ -84,8 +119,8 @@ func TestConditionIn(t *testing.T) {
 				field: "id",
 				value: []string{"A", "B", "C"},
 			},
-			`"id" NOT IN (?,?,?)`,
-			[]interface{}{"A", "B", "C"},
+			`"id" <> ALL(?)`,
+			[]interface{}{pq.Array([]interface{}{"A", "B", "C"})},
 			caseAndNotIn,
 		},
 		{
@@ -96,8 +131,8 @@ func TestConditionIn(t *testing.T) {
 				value: []string{"2016"},
 				funcs: []string{"YEAR"},
 			},
-			`YEAR("updated_at") NOT IN (?)`,
-			[]interface{}{"2016"},
+			`YEAR("updated_at") <> ALL(?)`,
+			[]interface{}{pq.Array([]interface{}{"2016"})},
 			caseAndNotIn,
 		},
 		{
@@ -107,8 +142,8 @@ func TestConditionIn(t *testing.T) {
 				field: "data.name",
 				value: []string{"A", "B"},
 			},
-			`("data_en" @> '{"name":["?"]}'::JSONB OR '{"name":["?"]}'::JSONB)`,
-			[]interface{}{"A", "B"},
+			`EXISTS (SELECT 1 FROM jsonb_array_elements_text(CASE jsonb_typeof("data_en"->'name') WHEN 'array' THEN "data_en"->'name' END) "elem" WHERE "elem" = ANY(?))`,
+			[]interface{}{pq.Array([]interface{}{"A", "B"})},
 			caseAndIn,
 		},
 		{
@@ -118,8 +153,8 @@ func TestConditionIn(t *testing.T) {
 				field: "data.name",
 				value: []string{"A", "B"},
 			},
-			`NOT("data_en" @> '{"name":["?"]}'::JSONB OR '{"name":["?"]}'::JSONB)`,
-			[]interface{}{"A", "B"},
+			`NOT EXISTS (SELECT 1 FROM jsonb_array_elements_text(CASE jsonb_typeof("data_en"->'name') WHEN 'array' THEN "data_en"->'name' END) "elem" WHERE "elem" = ANY(?))`,
+			[]interface{}{pq.Array([]interface{}{"A", "B"})},
 			caseAndNotIn,
 		},
 		{
@@ -130,8 +165,8 @@ func TestConditionIn(t *testing.T) {
 				value: []string{"video", "audio"},
 				funcs: []string{"LOWER"},
 			},
-			`("data_en" @> '{"badge":["?"]}'::JSONB OR '{"badge":["?"]}'::JSONB)`,
-			[]interface{}{"video", "audio"},
+			`EXISTS (SELECT 1 FROM jsonb_array_elements_text(CASE jsonb_typeof("data_en"->'badge') WHEN 'array' THEN "data_en"->'badge' END) "elem" WHERE "elem" = ANY(?))`,
+			[]interface{}{pq.Array([]interface{}{"video", "audio"})},
 			caseAndIn,
 		},
 		{
@@ -142,8 +177,8 @@ func TestConditionIn(t *testing.T) {
 				value: []string{"video", "audio"},
 				funcs: []string{"LOWER"},
 			},
-			`NOT("data_en" @> '{"badge":["?"]}'::JSONB OR '{"badge":["?"]}'::JSONB)`,
-			[]interface{}{"video", "audio"},
+			`NOT EXISTS (SELECT 1 FROM jsonb_array_elements_text(CASE jsonb_typeof("data_en"->'badge') WHEN 'array' THEN "data_en"->'badge' END) "elem" WHERE "elem" = ANY(?))`,
+			[]interface{}{pq.Array([]interface{}{"video", "audio"})},
 			caseAndNotIn,
 		},
 		{
@@ -153,8 +188,8 @@ func TestConditionIn(t *testing.T) {
 				field: "relations.name",
 				value: []string{"A", "B"},
 			},
-			`("data_en" @> '{"name":["?"]}'::JSONB OR '{"name":["?"]}'::JSONB)`,
-			[]interface{}{"A", "B"},
+			`EXISTS (SELECT 1 FROM jsonb_array_elements_text(CASE jsonb_typeof("data_en"->'name') WHEN 'array' THEN "data_en"->'name' END) "elem" WHERE "elem" = ANY(?))`,
+			[]interface{}{pq.Array([]interface{}{"A", "B"})},
 			caseAndIn,
 		},
 		{
@@ -164,8 +199,8 @@ func TestConditionIn(t *testing.T) {
 				field: "relations.name",
 				value: []string{"A", "B"},
 			},
-			`NOT("data_en" @> '{"name":["?"]}'::JSONB OR '{"name":["?"]}'::JSONB)`,
-			[]interface{}{"A", "B"},
+			`NOT EXISTS (SELECT 1 FROM jsonb_array_elements_text(CASE jsonb_typeof("data_en"->'name') WHEN 'array' THEN "data_en"->'name' END) "elem" WHERE "elem" = ANY(?))`,
+			[]interface{}{pq.Array([]interface{}{"A", "B"})},
 			caseAndNotIn,
 		},
 		{
@@ -176,8 +211,8 @@ func TestConditionIn(t *testing.T) {
 				value: []string{"video", "audio"},
 				funcs: []string{"LOWER"},
 			},
-			`("data_en" @> '{"badge":["?"]}'::JSONB OR '{"badge":["?"]}'::JSONB)`,
-			[]interface{}{"video", "audio"},
+			`EXISTS (SELECT 1 FROM jsonb_array_elements_text(CASE jsonb_typeof("data_en"->'badge') WHEN 'array' THEN "data_en"->'badge' END) "elem" WHERE "elem" = ANY(?))`,
+			[]interface{}{pq.Array([]interface{}{"video", "audio"})},
 			caseAndIn,
 		},
 		{
@@ -188,8 +223,8 @@ func TestConditionIn(t *testing.T) {
 				value: []string{"video", "audio"},
 				funcs: []string{"LOWER"},
 			},
-			`NOT("data_en" @> '{"badge":["?"]}'::JSONB OR '{"badge":["?"]}'::JSONB)`,
-			[]interface{}{"video", "audio"},
+			`NOT EXISTS (SELECT 1 FROM jsonb_array_elements_text(CASE jsonb_typeof("data_en"->'badge') WHEN 'array' THEN "data_en"->'badge' END) "elem" WHERE "elem" = ANY(?))`,
+			[]interface{}{pq.Array([]interface{}{"video", "audio"})},
 			caseAndNotIn,
 		},
 
@@ -200,8 +235,8 @@ func TestConditionIn(t *testing.T) {
 				field: "id",
 				value: []string{"A", "B", "C"},
 			},
-			`"id" IN (?,?,?)`,
-			[]interface{}{"A", "B", "C"},
+			`"id" = ANY(?)`,
+			[]interface{}{pq.Array([]interface{}{"A", "B", "C"})},
 			caseOrIn,
 		},
 		{
@@ -212,8 +247,8 @@ func TestConditionIn(t *testing.T) {
 				value: []string{"2019"},
 				funcs: []string{"YEAR"},
 			},
-			`YEAR("updated_at") IN (?)`,
-			[]interface{}{"2019"},
+			`YEAR("updated_at") = ANY(?)`,
+			[]interface{}{pq.Array([]interface{}{"2019"})},
 			caseOrIn,
 		},
 		{
@@ -223,8 +258,8 @@ func TestConditionIn(t *testing.T) {
 				field: "id",
 				value: []string{"A", "B", "C"},
 			},
-			`"id" NOT IN (?,?,?)`,
-			[]interface{}{"A", "B", "C"},
+			`"id" <> ALL(?)`,
+			[]interface{}{pq.Array([]interface{}{"A", "B", "C"})},
 			caseOrNotIn,
 		},
 		{
@@ -235,8 +270,8 @@ func TestConditionIn(t *testing.T) {
 				value: []string{"2015"},
 				funcs: []string{"YEAR"},
 			},
-			`YEAR("updated_at") NOT IN (?)`,
-			[]interface{}{"2015"},
+			`YEAR("updated_at") <> ALL(?)`,
+			[]interface{}{pq.Array([]interface{}{"2015"})},
 			caseOrNotIn,
 		},
 		{
@@ -246,8 +281,8 @@ func TestConditionIn(t *testing.T) {
 				field: "data.name",
 				value: []string{"A", "B"},
 			},
-			`("data_en" @> '{"name":["?"]}'::JSONB OR '{"name":["?"]}'::JSONB)`,
-			[]interface{}{"A", "B"},
+			`EXISTS (SELECT 1 FROM jsonb_array_elements_text(CASE jsonb_typeof("data_en"->'name') WHEN 'array' THEN "data_en"->'name' END) "elem" WHERE "elem" = ANY(?))`,
+			[]interface{}{pq.Array([]interface{}{"A", "B"})},
 			caseOrIn,
 		},
 		{
@@ -257,8 +292,8 @@ func TestConditionIn(t *testing.T) {
 				field: "data.name",
 				value: []string{"A", "B"},
 			},
-			`NOT("data_en" @> '{"name":["?"]}'::JSONB OR '{"name":["?"]}'::JSONB)`,
-			[]interface{}{"A", "B"},
+			`NOT EXISTS (SELECT 1 FROM jsonb_array_elements_text(CASE jsonb_typeof("data_en"->'name') WHEN 'array' THEN "data_en"->'name' END) "elem" WHERE "elem" = ANY(?))`,
+			[]interface{}{pq.Array([]interface{}{"A", "B"})},
 			caseOrNotIn,
 		},
 		{
@@ -269,8 +304,8 @@ func TestConditionIn(t *testing.T) {
 				value: []string{"video", "audio"},
 				funcs: []string{"LOWER"},
 			},
-			`("data_en" @> '{"badge":["?"]}'::JSONB OR '{"badge":["?"]}'::JSONB)`,
-			[]interface{}{"video", "audio"},
+			`EXISTS (SELECT 1 FROM jsonb_array_elements_text(CASE jsonb_typeof("data_en"->'badge') WHEN 'array' THEN "data_en"->'badge' END) "elem" WHERE "elem" = ANY(?))`,
+			[]interface{}{pq.Array([]interface{}{"video", "audio"})},
 			caseOrIn,
 		},
 		{
@@ -281,8 +316,8 @@ func TestConditionIn(t *testing.T) {
 				value: []string{"video", "audio"},
 				funcs: []string{"LOWER"},
 			},
-			`NOT("data_en" @> '{"badge":["?"]}'::JSONB OR '{"badge":["?"]}'::JSONB)`,
-			[]interface{}{"video", "audio"},
+			`NOT EXISTS (SELECT 1 FROM jsonb_array_elements_text(CASE jsonb_typeof("data_en"->'badge') WHEN 'array' THEN "data_en"->'badge' END) "elem" WHERE "elem" = ANY(?))`,
+			[]interface{}{pq.Array([]interface{}{"video", "audio"})},
 			caseOrNotIn,
 		},
 		{
@@ -292,8 +327,8 @@ func TestConditionIn(t *testing.T) {
 				field: "relations.name",
 				value: []string{"A", "B"},
 			},
-			`("data_en" @> '{"name":["?"]}'::JSONB OR '{"name":["?"]}'::JSONB)`,
-			[]interface{}{"A", "B"},
+			`EXISTS (SELECT 1 FROM jsonb_array_elements_text(CASE jsonb_typeof("data_en"->'name') WHEN 'array' THEN "data_en"->'name' END) "elem" WHERE "elem" = ANY(?))`,
+			[]interface{}{pq.Array([]interface{}{"A", "B"})},
 			caseOrIn,
 		},
 		{
@@ -303,8 +338,8 @@ func TestConditionIn(t *testing.T) {
 				field: "relations.name",
 				value: []string{"A", "B"},
 			},
-			`NOT("data_en" @> '{"name":["?"]}'::JSONB OR '{"name":["?"]}'::JSONB)`,
-			[]interface{}{"A", "B"},
+			`NOT EXISTS (SELECT 1 FROM jsonb_array_elements_text(CASE jsonb_typeof("data_en"->'name') WHEN 'array' THEN "data_en"->'name' END) "elem" WHERE "elem" = ANY(?))`,
+			[]interface{}{pq.Array([]interface{}{"A", "B"})},
 			caseOrNotIn,
 		},
 		{
@@ -315,8 +350,8 @@ func TestConditionIn(t *testing.T) {
 				value: []string{"video", "audio"},
 				funcs: []string{"LOWER"},
 			},
-			`("data_en" @> '{"badge":["?"]}'::JSONB OR '{"badge":["?"]}'::JSONB)`,
-			[]interface{}{"video", "audio"},
+			`EXISTS (SELECT 1 FROM jsonb_array_elements_text(CASE jsonb_typeof("data_en"->'badge') WHEN 'array' THEN "data_en"->'badge' END) "elem" WHERE "elem" = ANY(?))`,
+			[]interface{}{pq.Array([]interface{}{"video", "audio"})},
 			caseOrIn,
 		},
 		{
@@ -327,8 +362,8 @@ func TestConditionIn(t *testing.T) {
 				value: []string{"video", "audio"},
 				funcs: []string{"LOWER"},
 			},
-			`NOT("data_en" @> '{"badge":["?"]}'::JSONB OR '{"badge":["?"]}'::JSONB)`,
-			[]interface{}{"video", "audio"},
+			`NOT EXISTS (SELECT 1 FROM jsonb_array_elements_text(CASE jsonb_typeof("data_en"->'badge') WHEN 'array' THEN "data_en"->'badge' END) "elem" WHERE "elem" = ANY(?))`,
+			[]interface{}{pq.Array([]interface{}{"video", "audio"})},
 			caseOrNotIn,
 		},
 	}
@@ -357,3 +392,31 @@ func TestConditionIn(t *testing.T) {
 		})
 	}
 }
+
+func TestConditionIn_ArrayParam(t *testing.T) {
+	sql, values, err := somesql.NewSelect("fr").Fallback("en").Fields("id").Where(somesql.AndNotIn("fr", "data.seo.keywords", []string{"a", "b", "c"})).Where(somesql.And("fr", "type", "=", "article")).Build()
+
+	assert.Nil(t, err)
+	assert.Equal(t, `SELECT "id", CASE WHEN NULLIF("data_fr", '{}'::JSONB) IS NOT NULL THEN 'fr' ELSE 'en' END "data_lang" FROM repo WHERE NOT EXISTS (SELECT 1 FROM jsonb_array_elements_text(CASE jsonb_typeof(COALESCE(NULLIF("data_fr", '{}'::JSONB), "data_en")#>'{seo,keywords}') WHEN 'array' THEN COALESCE(NULLIF("data_fr", '{}'::JSONB), "data_en")#>'{seo,keywords}' END) "elem" WHERE "elem" = ANY($1)) AND "type" = $2 LIMIT 10`, sql, "values of inner fields are bound as a single array")
+	assert.Equal(t, []interface{}{pq.Array([]interface{}{"a", "b", "c"}), "article"}, values)
+}
+
+func TestConditionIn_StableSQL(t *testing.T) {
+	ids := make([]string, 20000)
+	for i := range ids {
+		ids[i] = fmt.Sprintf("id-%d", i)
+	}
+
+	sql, values, err := somesql.NewSelect("en").Fields("id").Where(somesql.AndIn("en", "id", ids)).Build()
+	assert.Nil(t, err)
+	assert.Equal(t, `SELECT "id" FROM repo WHERE "id" = ANY($1) LIMIT 10`, sql)
+	assert.Len(t, values, 1, "values are bound as a single array")
+
+	other, _, _ := somesql.NewSelect("en").Fields("id").Where(somesql.AndIn("en", "id", ids[:2])).Build()
+	assert.Equal(t, sql, other, "SQL does not depend on the number of values")
+
+	sql, values, err = somesql.NewDelete("en").Where(somesql.AndNotIn("en", "owner_id", []int64{1, 2})).Build()
+	assert.Nil(t, err)
+	assert.Equal(t, `DELETE FROM repo WHERE "owner_id" <> ALL($1)`, sql)
+	assert.Equal(t, []interface{}{pq.Array([]interface{}{int64(1), int64(2)})}, values)
+}
@@ -0,0 +1,108 @@
+package somesql
+
+// AndLike creates an AND conditional clause matching the LIKE pattern
+// AndLike("en", "data.title", "a%") yields: AND "data_en"->>'title' LIKE ?
+func AndLike(lang, field, pattern string, funcs ...string) ConditionClause {
+	return and(lang, field, OpLike, pattern, funcs...)
+}
+
+// OrLike creates an OR conditional clause matching the LIKE pattern
+func OrLike(lang, field, pattern string, funcs ...string) ConditionClause {
+	return or(lang, field, OpLike, pattern, funcs...)
+}
+
+// AndILike creates an AND conditional clause matching the LIKE pattern, case insensitive
+func AndILike(lang, field, pattern string, funcs ...string) ConditionClause {
+	return and(lang, field, OpILike, pattern, funcs...)
+}
+
+// OrILike creates an OR conditional clause matching the LIKE pattern, case insensitive
+func OrILike(lang, field, pattern string, funcs ...string) ConditionClause {
+	return or(lang, field, OpILike, pattern, funcs...)
+}
+
+// AndMatch creates an AND conditional clause matching the regular expression
+// AndMatch("en", "data.title", "^a") yields: AND "data_en"->>'title' ~ ?
+func AndMatch(lang, field, pattern string, funcs ...string) ConditionClause {
+	return and(lang, field, OpMatch, pattern, funcs...)
+}
+
+// OrMatch creates an OR conditional clause matching the regular expression
+func OrMatch(lang, field, pattern string, funcs ...string) ConditionClause {
+	return or(lang, field, OpMatch, pattern, funcs...)
+}
+
+// AndBetween creates an AND conditional clause of field between from and to (inclusive)
+// AndBetween("en", "data.views", 1, 10) yields: AND ("data_en"->>'views')::NUMERIC BETWEEN ? AND ?
+func AndBetween(lang, field string, from, to interface{}, funcs ...string) ConditionClause {
+	return and(lang, field, OpBetween, []interface{}{from, to}, funcs...)
+}
+
+// OrBetween creates an OR conditional clause of field between from and to (inclusive)
+func OrBetween(lang, field string, from, to interface{}, funcs ...string) ConditionClause {
+	return or(lang, field, OpBetween, []interface{}{from, to}, funcs...)
+}
+
+// AndIsNull creates an AND conditional clause of field being NULL (or missing for inner fields)
+// AndIsNull("en", "data.title") yields: AND "data_en"->>'title' IS NULL
+func AndIsNull(lang, field string) ConditionClause {
+	return and(lang, field, OpIsNull, nil)
+}
+
+// OrIsNull creates an OR conditional clause of field being NULL (or missing for inner fields)
+func OrIsNull(lang, field string) ConditionClause {
+	return or(lang, field, OpIsNull, nil)
+}
+
+// AndIsNotNull creates an AND conditional clause of field not being NULL
+func AndIsNotNull(lang, field string) ConditionClause {
+	return and(lang, field, OpIsNotNull, nil)
+}
+
+// OrIsNotNull creates an OR conditional clause of field not being NULL
+func OrIsNotNull(lang, field string) ConditionClause {
+	return or(lang, field, OpIsNotNull, nil)
+}
+
+// AndHasKey creates an AND conditional clause of JSON field having key (or the string element for arrays)
+// AndHasKey("en", "data.seo", "title") yields: AND "data_en"->'seo' ? $1
+func AndHasKey(lang, field, key string) ConditionClause {
+	return and(lang, field, OpHasKey, key)
+}
+
+// OrHasKey creates an OR conditional clause of JSON field having key (or the string element for arrays)
+func OrHasKey(lang, field, key string) ConditionClause {
+	return or(lang, field, OpHasKey, key)
+}
+
+// AndHasAnyKey creates an AND conditional clause of JSON field having any of keys
+// AndHasAnyKey("en", "data.seo", "title", "description") yields: AND "data_en"->'seo' ?| ARRAY[$1, $2]::TEXT[]
+func AndHasAnyKey(lang, field string, keys ...string) ConditionClause {
+	return and(lang, field, OpHasAnyKey, keys)
+}
+
+// OrHasAnyKey creates an OR conditional clause of JSON field having any of keys
+func OrHasAnyKey(lang, field string, keys ...string) ConditionClause {
+	return or(lang, field, OpHasAnyKey, keys)
+}
+
+// AndHasAllKeys creates an AND conditional clause of JSON field having all of keys
+func AndHasAllKeys(lang, field string, keys ...string) ConditionClause {
+	return and(lang, field, OpHasAllKeys, keys)
+}
+
+// OrHasAllKeys creates an OR conditional clause of JSON field having all of keys
+func OrHasAllKeys(lang, field string, keys ...string) ConditionClause {
+	return or(lang, field, OpHasAllKeys, keys)
+}
+
+// AndContains creates an AND conditional clause of JSON field containing value, sent as a JSON document
+// AndContains("en", "relations.tags", []string{"news"}) yields: AND "data_en"->'tags' @> ?::JSONB
+func AndContains(lang, field string, value interface{}) ConditionClause {
+	return and(lang, field, OpContains, value)
+}
+
+// OrContains creates an OR conditional clause of JSON field containing value, sent as a JSON document
+func OrContains(lang, field string, value interface{}) ConditionClause {
+	return or(lang, field, OpContains, value)
+}
@@ -0,0 +1,150 @@
+package somesql_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.lsl.digital/lardwaz/somesql"
+)
+
+func TestConditionOperators(t *testing.T) {
+	type testcase struct {
+		name      string
+		condition somesql.ConditionClause
+		sql       string
+		values    []interface{}
+		caseType  uint8
+	}
+
+	tests := []testcase{
+		{
+			"AND LIKE",
+			somesql.AndLike("en", "data.title", "a%"),
+			`"data_en"->>'title' LIKE ?`,
+			[]interface{}{"a%"},
+			somesql.AndCondition,
+		},
+		{
+			"OR ILIKE (function)",
+			somesql.OrILike("en", "type", "A%", "TRIM"),
+			`TRIM("type") ILIKE ?`,
+			[]interface{}{"A%"},
+			somesql.OrCondition,
+		},
+		{
+			"AND regex",
+			somesql.AndMatch("en", "data.seo.title", "^a"),
+			`"data_en"#>>'{seo,title}' ~ ?`,
+			[]interface{}{"^a"},
+			somesql.AndCondition,
+		},
+		{
+			"AND BETWEEN (meta)",
+			somesql.AndBetween("en", "created_at", "2020-01-01", "2020-12-31"),
+			`"created_at" BETWEEN ? AND ?`,
+			[]interface{}{"2020-01-01", "2020-12-31"},
+			somesql.AndCondition,
+		},
+		{
+			"OR BETWEEN (numbers of inner field)",
+			somesql.OrBetween("en", "data.views", 1, 10),
+			`("data_en"->>'views')::NUMERIC BETWEEN ? AND ?`,
+			[]interface{}{1, 10},
+			somesql.OrCondition,
+		},
+		{
+			"AND NOT BETWEEN (value function)",
+			somesql.And("en", "data.title", somesql.OpNotBetween, []string{"a", "m"}, "LOWER", "LOWER"),
+			`LOWER("data_en"->>'title') NOT BETWEEN LOWER(?) AND LOWER(?)`,
+			[]interface{}{"a", "m"},
+			somesql.AndCondition,
+		},
+		{
+			"AND IS NULL (meta)",
+			somesql.AndIsNull("en", "owner_id"),
+			`"owner_id" IS NULL`,
+			[]interface{}{},
+			somesql.AndCondition,
+		},
+		{
+			"OR IS NOT NULL (inner field)",
+			somesql.OrIsNotNull("en", "data.seo.title"),
+			`"data_en"#>>'{seo,title}' IS NOT NULL`,
+			[]interface{}{},
+			somesql.OrCondition,
+		},
+		{
+			"AND IS NULL (relations)",
+			somesql.AndIsNull("en", "relations.tags"),
+			`"data_en"->>'tags' IS NULL`,
+			[]interface{}{},
+			somesql.AndCondition,
+		},
+		{
+			"AND has key",
+			somesql.AndHasKey("en", "data.seo", "title"),
+			`"data_en"->'seo' £ ?`,
+			[]interface{}{"title"},
+			somesql.AndCondition,
+		},
+		{
+			"OR has key (data)",
+			somesql.OrHasKey("en", "data", "title"),
+			`"data_en" £ ?`,
+			[]interface{}{"title"},
+			somesql.OrCondition,
+		},
+		{
+			"AND has any key",
+			somesql.AndHasAnyKey("en", "relations.tags", "news", "sport"),
+			`"data_en"->'tags' £| ARRAY[?, ?]::TEXT[]`,
+			[]interface{}{"news", "sport"},
+			somesql.AndCondition,
+		},
+		{
+			"OR has all keys",
+			somesql.OrHasAllKeys("en", "data.seo.meta", "title", "description"),
+			`"data_en"#>'{seo,meta}' £& ARRAY[?, ?]::TEXT[]`,
+			[]interface{}{"title", "description"},
+			somesql.OrCondition,
+		},
+		{
+			"AND contains",
+			somesql.AndContains("en", "relations.tags", []string{"news"}),
+			`"data_en"->'tags' @> ?::JSONB`,
+			[]interface{}{`["news"]`},
+			somesql.AndCondition,
+		},
+		{
+			"OR contains (object)",
+			somesql.OrContains("en", "data", map[string]interface{}{"published": true}),
+			`"data_en" @> ?::JSONB`,
+			[]interface{}{`{"published":true}`},
+			somesql.OrCondition,
+		},
+	}
+
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sql, values := tt.condition.AsSQL()
+
+			assert.Equal(t, tt.sql, sql, fmt.Sprintf("%d: SQL invalid", i+1))
+			assert.Equal(t, tt.values, values, fmt.Sprintf("%d: Values invalid", i+1))
+			assert.Equal(t, tt.caseType, tt.condition.ConditionType(), fmt.Sprintf("%d: Condition type invalid", i+1))
+		})
+	}
+}
+
+func TestConditionOperators_Select(t *testing.T) {
+	sql, values, err := somesql.NewSelect("fr").Fallback("en").Fields("id").
+		Where(somesql.AndHasKey("fr", "data.seo", "title")).
+		Where(somesql.AndHasAnyKey("fr", "relations.tags", "news", "sport")).
+		Where(somesql.AndContains("fr", "data", map[string]interface{}{"published": true})).
+		Where(somesql.AndBetween("fr", "data.views", 1, 10)).
+		Build()
+
+	assert.Nil(t, err)
+	assert.Equal(t, `SELECT "id", CASE WHEN NULLIF("data_fr", '{}'::JSONB) IS NOT NULL THEN 'fr' ELSE 'en' END "data_lang" FROM repo WHERE COALESCE(NULLIF("data_fr", '{}'::JSONB), "data_en")->'seo' ? $1 AND COALESCE(NULLIF("data_fr", '{}'::JSONB), "data_en")->'tags' ?| ARRAY[$2, $3]::TEXT[] AND COALESCE(NULLIF("data_fr", '{}'::JSONB), "data_en") @> $4::JSONB AND (COALESCE(NULLIF("data_fr", '{}'::JSONB), "data_en")->>'views')::NUMERIC BETWEEN $5 AND $6 LIMIT 10`, sql, "key existence operators are not placeholders")
+	assert.Equal(t, []interface{}{"title", "news", "sport", `{"published":true}`, 1, 10}, values)
+}
@@ -1,10 +1,21 @@
 package somesql
 
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
 var (
 	andInQuery    = andOrInQuery(AndCondition, "IN")
 	orInQuery     = andOrInQuery(OrCondition, "IN")
 	andNotInQuery = andOrInQuery(AndCondition, "NOT IN")
 	orNotInQuery  = andOrInQuery(OrCondition, "NOT IN")
+
+	andExists    = andOrExists(AndCondition, "EXISTS")
+	orExists     = andOrExists(OrCondition, "EXISTS")
+	andNotExists = andOrExists(AndCondition, "NOT EXISTS")
+	orNotExists  = andOrExists(OrCondition, "NOT EXISTS")
 )
 
 // ConditionQuery defines conditions for a query
@@ -14,6 +25,7 @@ type ConditionQuery struct {
 	Operator string
 	Query    Accessor
 	Lang     string
+	ctx      conditionContext
 }
 
 func andOrInQuery(conditionType uint8, operator string) func(lang, field string, query Accessor) ConditionQuery {
@@ -48,11 +60,114 @@ func OrNotInQuery(lang, field string, query Accessor) ConditionQuery {
 	return orNotInQuery(lang, field, query)
 }
 
+func andOrExists(conditionType uint8, operator string) func(query Accessor) ConditionQuery {
+	return func(query Accessor) ConditionQuery {
+		return ConditionQuery{
+			Type:     conditionType,
+			Operator: operator,
+			Query:    query,
+		}
+	}
+}
+
+// AndExists returns a condition in the format EXISTS(SELECT ...) adjoined with AND
+// the sub-query may refer to the fields of the statement with Ref
+func AndExists(query Accessor) ConditionQuery {
+	return andExists(query)
+}
+
+// OrExists returns a condition in the format EXISTS(SELECT ...) adjoined with OR
+func OrExists(query Accessor) ConditionQuery {
+	return orExists(query)
+}
+
+// AndNotExists returns a condition in the format NOT EXISTS(SELECT ...) adjoined with AND
+func AndNotExists(query Accessor) ConditionQuery {
+	return andNotExists(query)
+}
+
+// OrNotExists returns a condition in the format NOT EXISTS(SELECT ...) adjoined with OR
+func OrNotExists(query Accessor) ConditionQuery {
+	return orNotExists(query)
+}
+
 // ConditionType return the condition type (or / and)
 func (c ConditionQuery) ConditionType() uint8 {
 	return c.Type
 }
 
+// withContext to satisfy interface contextCondition
+// the sub-query is rendered with the dialect of the statement, as an inner query without default limit
+// its placeholders are numbered along with the ones of the statement
+func (c ConditionQuery) withContext(ctx conditionContext) Condition {
+	c.ctx = ctx
+	c.Query = subQuery(c.Query)
+	if ctx.dialect != nil {
+		c.Query.SetDialect(ctx.dialect)
+	}
+	c.Query.SetInner(true)
+	return c
+}
+
+// subQuery returns a copy of query without the default limit, for the statement of the caller to be left as is
+// limits set with Limit are kept
+func subQuery(query Accessor) Accessor {
+	switch q := query.(type) {
+	case *Select:
+		s := *q
+		if !s.hasLimit {
+			s.limit = 0
+		}
+		return &s
+	case *MemorySelect:
+		s := *q
+		s.Select = subQuery(q.Select).(*Select)
+		return &s
+	case MemorySelect:
+		q.Select = subQuery(q.Select).(*Select)
+		return q
+	}
+
+	return query
+}
+
+// isExists returns true for conditions in the format EXISTS(SELECT ...)
+func (c ConditionQuery) isExists() bool {
+	return c.Operator == "EXISTS" || c.Operator == "NOT EXISTS"
+}
+
+// check to satisfy interface checkedCondition
+// errors of the sub-query are reported on the field
+func (c ConditionQuery) check(table Table) []error {
+	var (
+		errs  []error
+		field = c.Field
+	)
+
+	if c.isExists() {
+		if _, _, err := subQuery(c.Query).Build(); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", c.Operator, err))
+		}
+		return errs
+	}
+
+	errs = checkLangs(table, c.Lang)
+
+	if !IsFieldMeta(field) && !IsFieldData(field) && !IsFieldRelations(field) && !strings.Contains(field, ".") {
+		field = FieldData + "." + field // defaults to inner field of data
+	}
+
+	if err := checkField(table, field); err != nil {
+		errs = append(errs, FieldError{Field: c.Field, Err: errors.Unwrap(err)})
+	}
+
+	if _, _, err := subQuery(c.Query).Build(); err != nil {
+		errs = append(errs, FieldError{Field: c.Field, Err: err})
+	}
+
+	return errs
+}
+
 // AsSQL returns part of SQL incuding the sub-query
 func (c ConditionQuery) AsSQL(in ...bool) (string, []interface{}) {
 	var (
@@ -60,16 +175,55 @@ func (c ConditionQuery) AsSQL(in ...bool) (string, []interface{}) {
 	)
 
 	if IsFieldMeta(c.Field) || IsFieldData(c.Field) || IsFieldRelations(c.Field) {
-		field = `"` + c.Field + `"`
+		field = quoteIdentifier(c.Field)
+	} else if innerField, ok := GetInnerField(FieldData, c.Field); ok {
+		field = getFallbackAccessor(c.ctx.getDialect(), FieldData, c.Lang, c.ctx.fallback, innerField, true)
 	} else {
-		field = `"` + GetLangFieldData(c.Lang) + `"->>'` + c.Field + `'`
+		field = getFallbackAccessor(c.ctx.getDialect(), FieldData, c.Lang, c.ctx.fallback, c.Field, true)
 	}
 
 	c.Query.ToSQL()
 
 	innerSQL, innerVals := c.Query.GetSQL(), c.Query.GetValues()
 
+	if c.isExists() {
+		return c.Operator + " (" + innerSQL + ")", innerVals
+	}
+
 	sql := field + " " + c.Operator + " (" + innerSQL + ")"
 
 	return sql, innerVals
 }
+
+// Reference is a value referring to a field of the statement a sub-query is within
+// the statement is named with As i.e NewSelect("en").As("author")
+type Reference struct {
+	Alias string
+	Field string
+}
+
+// Ref returns a reference to field of the statement named alias, to be compared with within a sub-query
+// And("en", "owner_id", "=", Ref("author", "id")) yields: "owner_id" = "author"."id"
+func Ref(alias, field string) Reference {
+	return Reference{Alias: alias, Field: field}
+}
+
+// check returns ErrInvalidIdentifier if the alias or the field are not valid identifiers
+func (r Reference) check() error {
+	return checkIdentifier(r.Alias+"."+r.Field, r.Alias+"."+r.Field)
+}
+
+// sql returns the SQL of the referenced field for lang, inner fields as text
+func (r Reference) sql(d Dialect, lang string) string {
+	alias := quoteIdentifier(r.Alias) + "."
+
+	if innerField, ok := GetInnerField(FieldData, r.Field); ok {
+		return d.JSONAccessor(alias+quoteIdentifier(GetLangFieldData(lang)), innerField, true)
+	} else if innerField, ok := GetInnerField(FieldRelations, r.Field); ok {
+		return d.JSONAccessor(alias+quoteIdentifier(GetLangFieldData(lang)), innerField, true)
+	} else if parent, innerField, ok := getInnerFieldAny(r.Field); ok {
+		return d.JSONAccessor(alias+quoteIdentifier(parent), innerField, true)
+	}
+
+	return alias + quoteIdentifier(GetLangField(r.Field, lang))
+}
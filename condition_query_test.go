@@ -136,3 +136,60 @@ func TestConditionQuery(t *testing.T) {
 		})
 	}
 }
+
+func TestConditionQuery_Exists(t *testing.T) {
+	inner := somesql.NewSelectInner("en").Fields("id").Where(somesql.And("en", "type", "=", "article")).Limit(0)
+
+	sql, values := somesql.AndExists(inner).AsSQL()
+	assert.Equal(t, `EXISTS (SELECT "id" FROM repo WHERE "type" = ?)`, sql)
+	assert.Equal(t, []interface{}{"article"}, values)
+
+	cQuery := somesql.OrNotExists(inner)
+	sql, _ = cQuery.AsSQL()
+	assert.Equal(t, `NOT EXISTS (SELECT "id" FROM repo WHERE "type" = ?)`, sql)
+	assert.Equal(t, somesql.OrCondition, cQuery.ConditionType())
+}
+
+func TestConditionQuery_Correlated(t *testing.T) {
+	query := somesql.NewSelect("en").As("author").Fields("id", "data.name").
+		Where(somesql.And("en", "type", "=", "author")).
+		Where(somesql.AndExists(somesql.NewSelectInner("en").Fields("id").
+			Where(somesql.And("en", "type", "=", "article")).
+			Where(somesql.And("en", "data.status", "=", "published")).
+			Where(somesql.And("en", "relations.author", "=", somesql.Ref("author", "id"))))).
+		Where(somesql.And("en", "data.name", "LIKE", "A%"))
+
+	sql, values, err := query.Build()
+	assert.Nil(t, err)
+	assert.Equal(t, `SELECT "id", json_build_object('name', "data_en"->'name') "data" FROM repo AS "author" WHERE "type" = $1 AND EXISTS (SELECT "id" FROM repo WHERE "type" = $2 AND "data_en"->>'status' = $3 AND (jsonb_path_exists("data_en", '$.author[*] ? (@ == $val)', json_object(ARRAY['val', "author"."id"::TEXT])::jsonb))) AND "data_en"->>'name' LIKE $4 LIMIT 10`, sql, "placeholders are numbered across statements")
+	assert.Equal(t, []interface{}{"author", "article", "published", "A%"}, values)
+
+	query.SetDialect(somesql.SQLite)
+	sql, _, err = query.Build()
+	assert.Nil(t, err)
+	assert.Equal(t, `SELECT "id", json_object('name', "data_en"->'$.name') "data" FROM repo AS "author" WHERE "type" = ? AND EXISTS (SELECT "id" FROM repo WHERE "type" = ? AND "data_en"->>'$.status' = ? AND (EXISTS (SELECT 1 FROM json_each("data_en", '$.author') WHERE "value" = CAST("author"."id" AS TEXT)))) AND "data_en"->>'$.name' LIKE ? LIMIT 10`, sql)
+
+	inner := somesql.NewSelect("en").Fields("id").Where(somesql.And("en", "data.name", "=", "Alice")).Where(somesql.And("en", "id", "<>", somesql.Ref("doc", "id")))
+	sql, values, err = somesql.NewSelect("en").As("doc").Fields("id").
+		Where(somesql.AndIn("en", "type", []string{"article", "page"})).
+		Where(somesql.AndInQuery("en", "owner_id", inner)).
+		Where(somesql.And("en", "data.views", ">", 10)).Limit(0).Build()
+	assert.Nil(t, err)
+	assert.Equal(t, `SELECT "id" FROM repo AS "doc" WHERE "type" = ANY($1) AND "owner_id" IN (SELECT "id" FROM repo WHERE "data_en"->>'name' = $2 AND "id" <> "doc"."id") AND "data_en"->>'views' > $3`, sql, "sub-queries are rendered as inner statements without limit")
+	assert.Len(t, values, 3)
+
+	assert.False(t, inner.IsInner(), "the sub-query of the caller is left as is")
+	assert.Empty(t, inner.GetSQL(), "the sub-query of the caller is not built")
+	sql, _, err = inner.Build()
+	assert.Nil(t, err)
+	assert.Equal(t, `SELECT "id" FROM repo WHERE "data_en"->>'name' = $1 AND "id" <> "doc"."id" LIMIT 10`, sql, "the sub-query of the caller keeps its limit")
+}
+
+func TestConditionQuery_Limit(t *testing.T) {
+	inner := somesql.NewSelect("en").Fields("id").Where(somesql.And("en", "type", "=", "article")).Limit(5)
+
+	sql, _, err := somesql.NewSelect("en").Fields("id").Where(somesql.AndInQuery("en", "id", inner)).Limit(0).Build()
+	assert.Nil(t, err)
+	assert.Equal(t, `SELECT "id" FROM repo WHERE "id" IN (SELECT "id" FROM repo WHERE "type" = $1 LIMIT 5)`, sql, "limits set on sub-queries are kept")
+	assert.Empty(t, inner.GetSQL(), "the sub-query of the caller is not built")
+}
@@ -0,0 +1,115 @@
+package somesql
+
+import (
+	"fmt"
+	"strings"
+)
+
+// SearchConfigs maps languages to their text search configuration
+// Languages not listed use the "simple" configuration
+var SearchConfigs = map[string]string{
+	"en": "english",
+	"fr": "french",
+}
+
+// ConditionSearch represents a full-text search on fields
+type ConditionSearch struct {
+	Type   uint8
+	Fields []string
+	Query  string
+	Lang   string
+	ctx    conditionContext
+}
+
+// AndSearch creates a full-text search condition adjoined with AND
+// AndSearch("en", "foo bar", "data.title", "data.body") yields: AND to_tsvector('english', title || ' ' || body) @@ websearch_to_tsquery('english', 'foo bar')
+func AndSearch(lang, query string, fields ...string) ConditionSearch {
+	return ConditionSearch{
+		Type:   AndCondition,
+		Fields: fields,
+		Query:  query,
+		Lang:   lang,
+	}
+}
+
+// OrSearch creates a full-text search condition adjoined with OR
+// OrSearch("en", "foo bar", "data.title", "data.body") yields: OR to_tsvector('english', title || ' ' || body) @@ websearch_to_tsquery('english', 'foo bar')
+func OrSearch(lang, query string, fields ...string) ConditionSearch {
+	return ConditionSearch{
+		Type:   OrCondition,
+		Fields: fields,
+		Query:  query,
+		Lang:   lang,
+	}
+}
+
+// ConditionType to satisfy interface Condition
+func (c ConditionSearch) ConditionType() uint8 {
+	return c.Type
+}
+
+// AsSQL to satisfy interface Condition
+func (c ConditionSearch) AsSQL(in ...bool) (string, []interface{}) {
+	d := c.ctx.getDialect()
+
+	return d.Search(c.Lang, getSearchText(d, c.Lang, c.ctx.fallback, c.Fields)), []interface{}{c.Query}
+}
+
+// withContext to satisfy interface contextCondition
+func (c ConditionSearch) withContext(ctx conditionContext) Condition {
+	c.ctx = ctx
+	return c
+}
+
+// check to satisfy interface checkedCondition
+// ErrEmptyClause is reported if there is no field to search within
+func (c ConditionSearch) check(table Table) []error {
+	errs := checkLangs(table, c.Lang)
+
+	if len(c.Fields) == 0 {
+		errs = append(errs, fmt.Errorf("%w: search without fields", ErrEmptyClause))
+	}
+
+	for _, f := range c.Fields {
+		if err := checkField(table, f); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errs
+}
+
+// GetSearchConfig returns the text search configuration of lang
+func GetSearchConfig(lang string) string {
+	if config, ok := SearchConfigs[lang]; ok {
+		return config
+	}
+
+	return "simple"
+}
+
+// getSearchText returns the text of fields, separated by spaces
+func getSearchText(d Dialect, lang string, fallback []string, fields []string) string {
+	var texts []string
+
+	for _, f := range fields {
+		texts = append(texts, `COALESCE(`+getSearchField(d, lang, fallback, f)+`, '')`)
+	}
+
+	if len(texts) == 0 {
+		texts = append(texts, `''`)
+	}
+
+	return strings.Join(texts, ` || ' ' || `)
+}
+
+// getSearchField returns the text of field, inner fields of data are read from data_<lang> (or across fallback langs)
+func getSearchField(d Dialect, lang string, fallback []string, field string) string {
+	if innerField, ok := GetInnerField(FieldData, field); ok {
+		return getFallbackAccessor(d, FieldData, lang, fallback, innerField, true)
+	} else if parent, innerField, ok := getInnerFieldAny(field); ok {
+		return d.JSONAccessor(quoteIdentifier(GetLangField(parent, lang)), innerField, true)
+	}
+
+	return quoteIdentifier(field)
+}
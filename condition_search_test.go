@@ -0,0 +1,53 @@
+package somesql_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.lsl.digital/lardwaz/somesql"
+)
+
+func TestConditionSearch(t *testing.T) {
+	type testcase struct {
+		name      string
+		condition somesql.ConditionSearch
+		condType  uint8
+		sql       string
+		values    []interface{}
+	}
+
+	tests := []testcase{
+		{
+			"AND search (english)",
+			somesql.AndSearch("en", "foo bar", "data.title", "data.body"),
+			somesql.AndCondition,
+			`to_tsvector('english', COALESCE("data_en"->>'title', '') || ' ' || COALESCE("data_en"->>'body', '')) @@ websearch_to_tsquery('english', ?)`,
+			[]interface{}{"foo bar"},
+		},
+		{
+			"OR search (french) nested",
+			somesql.OrSearch("fr", `"foo" -bar`, "data.seo.title"),
+			somesql.OrCondition,
+			`to_tsvector('french', COALESCE("data_fr"#>>'{seo,title}', '')) @@ websearch_to_tsquery('french', ?)`,
+			[]interface{}{`"foo" -bar`},
+		},
+		{
+			"AND search (unknown lang) other fields",
+			somesql.AndSearch("mu", "foo", "type", "archive.title"),
+			somesql.AndCondition,
+			`to_tsvector('simple', COALESCE("type", '') || ' ' || COALESCE("archive"->>'title', '')) @@ websearch_to_tsquery('simple', ?)`,
+			[]interface{}{"foo"},
+		},
+	}
+
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sql, values := tt.condition.AsSQL()
+
+			assert.Equal(t, tt.condType, tt.condition.ConditionType(), fmt.Sprintf("%d: Type invalid", i+1))
+			assert.Equal(t, tt.sql, sql, fmt.Sprintf("%d: SQL invalid", i+1))
+			assert.Equal(t, tt.values, values, fmt.Sprintf("%d: Values invalid", i+1))
+		})
+	}
+}
@@ -0,0 +1,201 @@
+package somesql
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+// ErrNotFound is returned when no document matches a statement
+var ErrNotFound = errors.New("document not found")
+
+// Document represents a single row retrieved from store
+// Data holds the decoded data_<lang> field (or inner fields requested)
+// Meta holds any other field requested (i.e fields of other tables)
+// Included holds the related documents embedded with Select.Include, by relation name
+// Lang is the lang data was read from
+// Translations holds the data of each lang projected with Select.Langs
+type Document struct {
+	ID        string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	OwnerID   string
+	Type      string
+	Data      map[string]interface{}
+	Meta      map[string]interface{}
+	Included  map[string][]Document
+	Lang      string
+
+	Translations map[string]map[string]interface{}
+}
+
+// set assigns the value of a column to the document
+func (d *Document) set(column string, value interface{}, lang string) error {
+	if b, ok := value.([]byte); ok {
+		value = string(b)
+	}
+
+	switch column {
+	case FieldID:
+		d.ID = asString(value)
+	case FieldOwnerID:
+		d.OwnerID = asString(value)
+	case FieldType:
+		d.Type = asString(value)
+	case FieldCreatedAt:
+		d.CreatedAt = asTime(value)
+	case FieldUpdatedAt:
+		d.UpdatedAt = asTime(value)
+	case FieldData, GetLangFieldData(lang):
+		data, err := decodeJSONB(value)
+		if err != nil {
+			return err
+		}
+		d.Data = data
+		if d.Lang == "" {
+			d.Lang = lang
+		}
+	case FieldDataLang:
+		d.Lang = asString(value)
+	default:
+		if translationLang, ok := GetInnerField(FieldTranslations, column); ok {
+			data, err := decodeJSONB(value)
+			if err != nil {
+				return err
+			}
+			if d.Translations == nil {
+				d.Translations = make(map[string]map[string]interface{})
+			}
+			d.Translations[translationLang] = data
+			if translationLang == lang {
+				d.Data, d.Lang = data, lang
+			}
+			return nil
+		}
+
+		if innerField, ok := GetInnerField(FieldRelations, column); ok {
+			docs, err := decodeDocuments(value, lang)
+			if err != nil {
+				return err
+			}
+			if d.Included == nil {
+				d.Included = make(map[string][]Document)
+			}
+			d.Included[innerField] = docs
+			return nil
+		}
+
+		if d.Meta == nil {
+			d.Meta = make(map[string]interface{})
+		}
+		d.Meta[column] = value
+	}
+
+	return nil
+}
+
+func asString(value interface{}) string {
+	if s, ok := value.(string); ok {
+		return s
+	}
+
+	return ""
+}
+
+// asTime returns value as time, embedded documents hold timestamps as text
+func asTime(value interface{}) time.Time {
+	if t, ok := value.(time.Time); ok {
+		return t
+	}
+
+	t, _ := time.Parse(time.RFC3339Nano, asString(value))
+
+	return t
+}
+
+func decodeJSONB(value interface{}) (map[string]interface{}, error) {
+	data := make(map[string]interface{})
+
+	if m, ok := value.(map[string]interface{}); ok {
+		return m, nil
+	}
+
+	s, ok := value.(string)
+	if !ok || s == "" {
+		return data, nil
+	}
+
+	if err := json.Unmarshal([]byte(s), &data); err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+// decodeDocuments returns the documents of a JSON array of objects
+func decodeDocuments(value interface{}, lang string) ([]Document, error) {
+	var (
+		objects []map[string]interface{}
+		docs    = make([]Document, 0)
+	)
+
+	s, ok := value.(string)
+	if !ok || s == "" {
+		return docs, nil
+	}
+
+	if err := json.Unmarshal([]byte(s), &objects); err != nil {
+		return nil, err
+	}
+
+	for _, object := range objects {
+		var doc Document
+		for column, v := range object {
+			if err := doc.set(column, v, lang); err != nil {
+				return nil, err
+			}
+		}
+		docs = append(docs, doc)
+	}
+
+	return docs, nil
+}
+
+// scanDocuments calls fn for each row as a Document
+func scanDocuments(rows *sql.Rows, lang string, fn func(Document) error) error {
+	defer rows.Close()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return err
+	}
+
+	for rows.Next() {
+		var (
+			doc    Document
+			values = make([]interface{}, len(columns))
+			dest   = make([]interface{}, len(columns))
+		)
+
+		for i := range values {
+			dest[i] = &values[i]
+		}
+
+		if err := rows.Scan(dest...); err != nil {
+			return err
+		}
+
+		for i, column := range columns {
+			if err := doc.set(column, values[i], lang); err != nil {
+				return err
+			}
+		}
+
+		if err := fn(doc); err != nil {
+			return err
+		}
+	}
+
+	return rows.Err()
+}
@@ -0,0 +1,138 @@
+package somesql
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDocumentSet(t *testing.T) {
+	now := time.Date(2009, time.November, 10, 23, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		lang     string
+		columns  []string
+		values   []interface{}
+		expected Document
+		err      bool
+	}{
+		{
+			name:    "meta fields + data_en",
+			lang:    "en",
+			columns: []string{"id", "created_at", "updated_at", "owner_id", "type", "data_en"},
+			values:  []interface{}{[]byte("1"), now, now, []byte("2"), "article", []byte(`{"title":"abc"}`)},
+			expected: Document{
+				ID:        "1",
+				CreatedAt: now,
+				UpdatedAt: now,
+				OwnerID:   "2",
+				Type:      "article",
+				Data:      map[string]interface{}{"title": "abc"},
+				Lang:      "en",
+			},
+		},
+		{
+			name:    "data_fr",
+			lang:    "fr",
+			columns: []string{"id", "data_fr"},
+			values:  []interface{}{"1", []byte(`{"title":"abc"}`)},
+			expected: Document{
+				ID:   "1",
+				Data: map[string]interface{}{"title": "abc"},
+				Lang: "fr",
+			},
+		},
+		{
+			name:    "inner fields",
+			lang:    "en",
+			columns: []string{"id", "data"},
+			values:  []interface{}{"1", []byte(`{"author":["a","b"]}`)},
+			expected: Document{
+				ID:   "1",
+				Data: map[string]interface{}{"author": []interface{}{"a", "b"}},
+				Lang: "en",
+			},
+		},
+		{
+			name:    "other fields",
+			lang:    "en",
+			columns: []string{"id", "position"},
+			values:  []interface{}{"1", int64(2)},
+			expected: Document{
+				ID:   "1",
+				Meta: map[string]interface{}{"position": int64(2)},
+			},
+		},
+		{
+			name:    "included documents",
+			lang:    "en",
+			columns: []string{"id", "relations.author"},
+			values:  []interface{}{"1", []byte(`[{"id":"2","created_at":"2009-11-10T23:00:00Z","data":{"name":"John"}}]`)},
+			expected: Document{
+				ID: "1",
+				Included: map[string][]Document{
+					"author": {{ID: "2", CreatedAt: now, Data: map[string]interface{}{"name": "John"}, Lang: "en"}},
+				},
+			},
+		},
+		{
+			name:    "fallback lang",
+			lang:    "fr",
+			columns: []string{"id", "data_fr", "data_lang"},
+			values:  []interface{}{"1", []byte(`{"title":"abc"}`), "en"},
+			expected: Document{
+				ID:   "1",
+				Data: map[string]interface{}{"title": "abc"},
+				Lang: "en",
+			},
+		},
+		{
+			name:    "translations",
+			lang:    "fr",
+			columns: []string{"id", "translations.en", "translations.fr"},
+			values:  []interface{}{"1", []byte(`{"title":"abc"}`), []byte(`{"title":"def"}`)},
+			expected: Document{
+				ID:   "1",
+				Data: map[string]interface{}{"title": "def"},
+				Lang: "fr",
+				Translations: map[string]map[string]interface{}{
+					"en": {"title": "abc"},
+					"fr": {"title": "def"},
+				},
+			},
+		},
+		{
+			name:    "invalid data",
+			lang:    "en",
+			columns: []string{"data_en"},
+			values:  []interface{}{[]byte(`{`)},
+			err:     true,
+		},
+	}
+
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var (
+				doc Document
+				err error
+			)
+
+			for c, column := range tt.columns {
+				if err = doc.set(column, tt.values[c], tt.lang); err != nil {
+					break
+				}
+			}
+
+			if tt.err {
+				assert.Error(t, err, fmt.Sprintf("%d: Error expected", i+1))
+				return
+			}
+
+			assert.NoError(t, err, fmt.Sprintf("%d: Unexpected error", i+1))
+			assert.Equal(t, tt.expected, doc, fmt.Sprintf("%d: Document invalid", i+1))
+		})
+	}
+}
@@ -0,0 +1,174 @@
+package somesql
+
+import (
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Errors of the statement builders
+// They are reported by Build (wrapped within FieldError and BuildError) and returned by Exec and Rows
+var (
+	// ErrUnknownField is returned for fields which are not fields of the table
+	ErrUnknownField = errors.New("unknown field")
+	// ErrUnsupportedValue is returned for values of a type which cannot be sent as a parameter
+	ErrUnsupportedValue = errors.New("unsupported value type")
+	// ErrInvalidJSON is returned for values of JSONB fields which cannot be encoded as JSON
+	ErrInvalidJSON = errors.New("invalid JSON value")
+	// ErrNoValues is returned for conditions having no value to compare with i.e IN of an empty list
+	ErrNoValues = errors.New("no values")
+	// ErrEmptyClause is returned for clauses having nothing to render i.e SET of an Update without fields
+	ErrEmptyClause = errors.New("empty clause")
+	// ErrValuesMismatch is returned when the number of values differs from the number of placeholders
+	ErrValuesMismatch = errors.New("number of values does not match placeholders")
+)
+
+// FieldError represents an error of the builder on a field
+type FieldError struct {
+	Field string
+	Err   error
+}
+
+// Error implements error
+func (e FieldError) Error() string {
+	return e.Field + ": " + e.Err.Error()
+}
+
+// Unwrap returns the error on the field
+func (e FieldError) Unwrap() error {
+	return e.Err
+}
+
+// BuildError aggregates the errors found while building a statement
+// errors.Is and errors.As match any of the errors
+type BuildError struct {
+	Errors []error
+}
+
+// Error implements error
+func (e BuildError) Error() string {
+	msgs := make([]string, len(e.Errors))
+	for i, err := range e.Errors {
+		msgs[i] = err.Error()
+	}
+
+	return strings.Join(msgs, "; ")
+}
+
+// Is returns true if any of the errors matches target
+func (e BuildError) Is(target error) bool {
+	for _, err := range e.Errors {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// As finds the first of the errors matching target
+func (e BuildError) As(target interface{}) bool {
+	for _, err := range e.Errors {
+		if errors.As(err, target) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// newBuildError returns errs as a single error, nil if there are none
+// a single error is returned as is
+func newBuildError(errs []error) error {
+	switch len(errs) {
+	case 0:
+		return nil
+	case 1:
+		return errs[0]
+	}
+
+	return BuildError{Errors: errs}
+}
+
+// isValue returns true if v can be sent as a parameter of a statement
+func isValue(v interface{}) bool {
+	_, err := driver.DefaultParameterConverter.ConvertValue(v)
+	return err == nil
+}
+
+// checkValues returns ErrUnsupportedValue for each of values which cannot be sent as a parameter
+func checkValues(field string, values []interface{}) []error {
+	var errs []error
+
+	for _, v := range values {
+		if !isValue(v) {
+			errs = append(errs, FieldError{Field: field, Err: fmt.Errorf("%w %T", ErrUnsupportedValue, v)})
+		}
+	}
+
+	return errs
+}
+
+// checkField returns ErrUnknownField if field is neither a field of table nor an inner field of its JSONB fields
+// keys of inner fields must be valid identifiers
+func checkField(table Table, field string) error {
+	if table.IsMeta(field) || table.IsJSONB(field) {
+		return nil
+	} else if _, innerField, ok := table.GetInnerField(field); ok {
+		return checkIdentifier(field, innerField)
+	}
+
+	return FieldError{Field: field, Err: ErrUnknownField}
+}
+
+// checkFields returns the errors of fields set for table, in order of fields
+// meta fields are sent as parameters as is, JSONB fields must be set by inner field
+func checkFields(table Table, fields Fields) []error {
+	var (
+		errs  []error
+		names = make([]string, 0, len(fields))
+	)
+
+	for f := range fields {
+		names = append(names, f)
+	}
+	sort.Strings(names)
+
+	for _, f := range names {
+		if table.IsMeta(f) {
+			errs = append(errs, checkValues(f, []interface{}{fields[f]})...)
+		} else if !table.IsJSONB(f) {
+			errs = append(errs, FieldError{Field: f, Err: ErrUnknownField})
+		} else if jsonbFields, ok := fields[f].(JSONBFields); !ok {
+			errs = append(errs, FieldError{Field: f, Err: fmt.Errorf("%w %T", ErrUnsupportedValue, fields[f])})
+		} else {
+			for _, innerField := range jsonbFields.keys {
+				if err := checkIdentifier(f+"."+innerField, innerField); err != nil {
+					errs = append(errs, err)
+				}
+			}
+		}
+	}
+
+	return errs
+}
+
+// appendError appends err to errs unless nil
+func appendError(errs []error, err error) []error {
+	if err != nil {
+		return append(errs, err)
+	}
+
+	return errs
+}
+
+// checkPlaceholders returns ErrValuesMismatch if the number of placeholders (?) of sql differs from the number of values
+func checkPlaceholders(sql string, values []interface{}) error {
+	if n := strings.Count(sql, "?"); n != len(values) {
+		return fmt.Errorf("%w: %d placeholders, %d values", ErrValuesMismatch, n, len(values))
+	}
+
+	return nil
+}
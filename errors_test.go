@@ -0,0 +1,170 @@
+package somesql_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.lsl.digital/lardwaz/somesql"
+)
+
+func TestStatement_Build(t *testing.T) {
+	type testCase struct {
+		name          string
+		query         somesql.Statement
+		expectedError error
+	}
+
+	tests := []testCase{
+		{
+			name:  "valid select",
+			query: somesql.NewSelect("en").Fields("id", "data.title").Where(somesql.And("en", "relations.tags", "=", "news")),
+		},
+		{
+			name:  "having on aggregate alias",
+			query: somesql.NewSelect("en").GroupBy("type").Count("count").Having(somesql.And("en", "count", ">", 1)),
+		},
+		{
+			name:          "select unknown field",
+			query:         somesql.NewSelect("en").Fields("id", "titel"),
+			expectedError: somesql.ErrUnknownField,
+		},
+		{
+			name:          "select include of non relations field",
+			query:         somesql.NewSelect("en").Include("data.author"),
+			expectedError: somesql.ErrUnknownField,
+		},
+		{
+			name:          "condition unknown field",
+			query:         somesql.NewSelect("en").Where(somesql.And("en", "titel", "=", "x")),
+			expectedError: somesql.ErrUnknownField,
+		},
+		{
+			name:          "condition unsupported value",
+			query:         somesql.NewSelect("en").Where(somesql.And("en", "data.views", "=", []int64{1, 2})),
+			expectedError: somesql.ErrUnsupportedValue,
+		},
+		{
+			name:          "condition several values",
+			query:         somesql.NewSelect("en").Where(somesql.And("en", "id", "=", []string{"a", "b"})),
+			expectedError: somesql.ErrUnsupportedValue,
+		},
+		{
+			name:          "condition IN without values",
+			query:         somesql.NewSelect("en").Where(somesql.AndIn("en", "id", []string{})),
+			expectedError: somesql.ErrNoValues,
+		},
+		{
+			name:          "empty condition group",
+			query:         somesql.NewSelect("en").Where(somesql.And("en", "id", "=", "a")).Where(somesql.OrGroup()),
+			expectedError: somesql.ErrEmptyClause,
+		},
+		{
+			name:          "search without fields",
+			query:         somesql.NewSelect("en").Where(somesql.AndSearch("en", "hello")),
+			expectedError: somesql.ErrEmptyClause,
+		},
+		{
+			name:          "rank of search without fields",
+			query:         somesql.NewSelect("en").OrderRank("hello"),
+			expectedError: somesql.ErrEmptyClause,
+		},
+		{
+			name:          "sub-query unknown field",
+			query:         somesql.NewSelect("en").Where(somesql.AndInQuery("en", "id", somesql.NewSelectInner("en").Fields("nope"))),
+			expectedError: somesql.ErrUnknownField,
+		},
+		{
+			name:          "insert without fields",
+			query:         somesql.NewInsert("en"),
+			expectedError: somesql.ErrEmptyClause,
+		},
+		{
+			name:          "insert of empty rows",
+			query:         somesql.NewInsert("en").Batch(somesql.NewFields(), somesql.NewFields()),
+			expectedError: somesql.ErrEmptyClause,
+		},
+		{
+			name:          "insert without rows",
+			query:         somesql.NewInsert("en").Batch(),
+			expectedError: somesql.ErrEmptyClause,
+		},
+		{
+			name:          "insert unknown field",
+			query:         somesql.NewInsert("en").Fields(somesql.NewFields().ID("a").Set("titel", "x")),
+			expectedError: somesql.ErrUnknownField,
+		},
+		{
+			name:          "insert malformed inner field",
+			query:         somesql.NewInsert("en").Fields(somesql.NewFields().ID("a").Set("data.", "x")),
+			expectedError: somesql.ErrUnknownField,
+		},
+		{
+			name:          "insert unsupported meta value",
+			query:         somesql.NewInsert("en").Fields(somesql.NewFields().Set("owner_id", []string{"a"})),
+			expectedError: somesql.ErrUnsupportedValue,
+		},
+		{
+			name:          "insert invalid JSON",
+			query:         somesql.NewInsert("en").Fields(somesql.NewFields().ID("a").Set("data.x", make(chan int))),
+			expectedError: somesql.ErrInvalidJSON,
+		},
+		{
+			name:          "update without fields",
+			query:         somesql.NewUpdate("en").Where(somesql.And("en", "id", "=", "a")),
+			expectedError: somesql.ErrEmptyClause,
+		},
+		{
+			name:          "update invalid JSON",
+			query:         somesql.NewUpdate("en").Fields(somesql.NewFields().Set("data.tags", []interface{}{make(chan int)})),
+			expectedError: somesql.ErrInvalidJSON,
+		},
+		{
+			name:          "update unsupported inner value",
+			query:         somesql.NewUpdate("en").Fields(somesql.NewFields().Set("data.seo", map[string]interface{}{"title": "x"})),
+			expectedError: somesql.ErrUnsupportedValue,
+		},
+		{
+			name:          "delete condition unknown field",
+			query:         somesql.NewDelete("en").Where(somesql.And("en", "titel", "=", "x")),
+			expectedError: somesql.ErrUnknownField,
+		},
+	}
+
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := tt.query.Build()
+
+			if tt.expectedError == nil {
+				assert.Nil(t, err, fmt.Sprintf("%02d. %s :: unexpected error", i+1, tt.name))
+				return
+			}
+
+			assert.True(t, errors.Is(err, tt.expectedError), fmt.Sprintf("%02d. %s :: invalid error %v", i+1, tt.name, err))
+		})
+	}
+}
+
+func TestStatement_BuildErrors(t *testing.T) {
+	var fieldErr somesql.FieldError
+
+	_, _, err := somesql.NewUpdate("en").Fields(somesql.NewFields().Set("titel", "x")).Where(somesql.AndIn("en", "id", []string{})).Build()
+	assert.True(t, errors.Is(err, somesql.ErrUnknownField), "unknown field is reported")
+	assert.True(t, errors.Is(err, somesql.ErrNoValues), "IN without values is reported")
+	assert.True(t, errors.Is(err, somesql.ErrEmptyClause), "empty SET is reported")
+	assert.True(t, errors.As(err, &fieldErr))
+	assert.Equal(t, "titel", fieldErr.Field)
+
+	// builder errors are returned before the database is used (no database is set)
+	assert.True(t, errors.Is(somesql.NewUpdate("en").Exec(true), somesql.ErrEmptyClause), "update exec")
+	assert.True(t, errors.Is(somesql.NewInsert("en").Fields(somesql.NewFields().Set("titel", "x")).Exec(true), somesql.ErrUnknownField), "insert exec")
+	assert.True(t, errors.Is(somesql.NewInsert("en").Exec(true), somesql.ErrEmptyClause), "empty insert exec")
+	assert.True(t, errors.Is(somesql.NewDelete("en").Where(somesql.OrGroup()).Exec(true), somesql.ErrEmptyClause), "delete exec")
+
+	_, err = somesql.NewSelect("en").Fields("titel").Rows()
+	assert.True(t, errors.Is(err, somesql.ErrUnknownField), "select rows")
+
+	_, err = somesql.NewMemory().Select(somesql.NewSelect("en").Where(somesql.AndIn("en", "id", []string{}))).All()
+	assert.True(t, errors.Is(err, somesql.ErrNoValues), "memory select")
+}
@@ -16,16 +16,33 @@ const (
 	FieldType      string = "type"
 	FieldData      string = "data"
 	FieldRelations string = "relations"
+)
 
-	NoneJSONBArr uint8 = iota
+// JSONB array actions
+// values start at 7 as they did when declared along with the Fields constants
+const (
+	NoneJSONBArr uint8 = iota + 7
 	JSONBArrAdd
 	JSONBArrRemove
+	JSONBArrAddUnique
+)
+
+// Fields of results, which are not columns
+const (
+	FieldDataLang     string = "data_lang"    // lang data was read from (see Select.Fallback)
+	FieldTranslations string = "translations" // data of each lang (see Select.Langs)
 )
 
 // Fields variables
 var (
+	// MetaFieldsList are the meta fields of TableRepo
+	//
+	// Deprecated: use TableRepo.MetaFields
 	MetaFieldsList = []string{FieldID, FieldCreatedAt, FieldUpdatedAt, FieldOwnerID, FieldType}
-	FieldsList     = append(MetaFieldsList, FieldData)
+	// FieldsList are the fields of TableRepo
+	//
+	// Deprecated: use the MetaFields and JSONBFields of TableRepo
+	FieldsList = append(MetaFieldsList, FieldData)
 )
 
 // JSONBField represents information about a single JSONB field
@@ -57,7 +74,7 @@ func (j *JSONBFields) Add(field string, value interface{}, action ...uint8) {
 
 	// if key already exist append to previous value
 	var newVal []interface{}
-	isAdd := act == JSONBArrAdd
+	isAdd := act == JSONBArrAdd || act == JSONBArrAddUnique
 	newValSlice, wasNewValAsserted := expandValues(value)
 	jsonbField, exist := j.data[field]
 	if exist && isAdd {
@@ -80,6 +97,9 @@ func (j *JSONBFields) Add(field string, value interface{}, action ...uint8) {
 		j.data[field] = JSONBField{Value: newValSlice, Action: act}
 	} else {
 		j.data[field] = JSONBField{Value: value, Action: act}
+	}
+
+	if !exist {
 		j.keys = append(j.keys, field)
 	}
 }
@@ -100,10 +120,11 @@ func (j JSONBFields) GetOrderedList() ([]string, []interface{}, []uint8) {
 }
 
 // Values returns the inner map
+// Nested fields (dot-separated) are set within their parents
 func (j JSONBFields) Values() map[string]interface{} {
 	values := make(map[string]interface{})
-	for f, v := range j.data {
-		values[f] = v.Value
+	for _, f := range j.keys {
+		setNestedValue(values, f, j.data[f].Value)
 	}
 	return values
 }
@@ -167,8 +188,8 @@ func (f Fields) Type(s string) Fields {
 }
 
 // Set assigns a new value to fields
-// Dot-seperated field name is treated as inner field of JSONB field (1 level only)
-// i.e data.author = data->>author
+// Dot-seperated field name is treated as inner field of JSONB field (nested with more dots)
+// i.e data.author = data->>author, data.seo.title = data#>>{seo,title}
 func (f Fields) Set(field string, value interface{}) Fields {
 	f.set(field, value, NoneJSONBArr)
 
@@ -182,6 +203,13 @@ func (f Fields) Add(field string, value interface{}) Fields {
 	return f
 }
 
+// AddUnique adds data to an array jsonb field, skipping elements already present
+func (f Fields) AddUnique(field string, value interface{}) Fields {
+	f.set(field, value, JSONBArrAddUnique)
+
+	return f
+}
+
 // Remove removes data from an array jsonb field
 func (f Fields) Remove(field string, value interface{}) Fields {
 	f.set(field, value, JSONBArrRemove)
@@ -190,49 +218,64 @@ func (f Fields) Remove(field string, value interface{}) Fields {
 }
 
 func (f Fields) set(field string, value interface{}, action uint8) {
-	if IsFieldMeta(field) || IsFieldData(field) || IsFieldRelations(field) {
+	if !strings.Contains(field, ".") {
 		f[field] = value
 	} else if innerField, ok := GetInnerField(FieldData, field); ok {
-		jsonbFields, ok := f[FieldData].(JSONBFields)
-		if !ok { // if not jsonbfields, make it
-			jsonbFields = NewJSONBFields()
-		}
-		jsonbFields.Add(innerField, value, action)
-		f[FieldData] = jsonbFields
+		f.setInner(FieldData, innerField, value, action)
 	} else if innerField, ok := GetInnerField(FieldRelations, field); ok {
-		jsonbFields, ok := f[FieldData].(JSONBFields)
-		if !ok { // if not jsonbfields, make it
-			jsonbFields = NewJSONBFields()
-		}
 		vals, _ := expandValues(value)
-		jsonbFields.Add(innerField, vals, action)
-		f[FieldData] = jsonbFields
+		if vals == nil { // relations are always arrays
+			vals = make([]interface{}, 0)
+		}
+		f.setInner(FieldData, innerField, vals, action)
+	} else if parent, innerField, ok := getInnerFieldAny(field); ok {
+		f.setInner(parent, innerField, value, action)
+	} else { // kept as is to be reported as unknown by statements
+		f[field] = value
+	}
+}
+
+func (f Fields) setInner(parent, innerField string, value interface{}, action uint8) {
+	jsonbFields, ok := f[parent].(JSONBFields)
+	if !ok { // if not jsonbfields, make it
+		jsonbFields = NewJSONBFields()
 	}
+	jsonbFields.Add(innerField, value, action)
+	f[parent] = jsonbFields
 }
 
-// List returns fields and values
+// List returns fields and values of TableRepo
+//
+// Deprecated: use ListOf
 func (f Fields) List() ([]string, []interface{}) {
+	return f.ListOf(TableRepo)
+}
+
+// ListOf returns fields and values of table t
+func (f Fields) ListOf(t Table) ([]string, []interface{}) {
 	var (
 		fields []string
 		values []interface{}
 	)
 
 	// Meta Fields
-	for _, field := range MetaFieldsList {
+	for _, field := range t.MetaFields {
 		if v, ok := f[field]; ok {
 			fields = append(fields, field)
 			values = append(values, v)
 		}
 	}
 
-	// Data Fields
-	if dataField, ok := f[FieldData].(JSONBFields); ok {
-		fields = append(fields, FieldData)
-		values = append(values, dataField)
+	// JSONB Fields
+	for _, field := range t.JSONBFields {
+		if jsonbField, ok := f[field].(JSONBFields); ok {
+			fields = append(fields, field)
+			values = append(values, jsonbField)
+		}
 	}
 
 	// Relations Fields
-	if relationsField, ok := f[FieldRelations].(JSONBFields); ok {
+	if relationsField, ok := f[FieldRelations].(JSONBFields); ok && t.IsJSONB(FieldRelations) {
 		fields = append(fields, FieldRelations)
 		values = append(values, relationsField)
 	}
@@ -266,14 +309,71 @@ func GetLangFieldData(lang string) string {
 }
 
 // GetInnerField returns the inner data field
+// Nested inner fields are dot-separated i.e data.seo.title => seo.title
 func GetInnerField(parent, field string) (string, bool) {
-	if strings.Count(field, ".") == 1 {
-		parts := strings.Split(field, ".")
-		if parts[0] != parent && parts[1] != "" {
+	parts := strings.Split(field, ".")
+	if len(parts) < 2 || parts[0] != parent {
+		return "", false
+	}
+
+	for _, part := range parts[1:] {
+		if part == "" {
 			return "", false
 		}
-		return parts[1], true
 	}
 
-	return "", false
+	return strings.Join(parts[1:], "."), true
+}
+
+// getJSONBPath returns innerField as a text array path i.e seo.title => '{seo,title}'
+func getJSONBPath(innerField string) string {
+	return quoteLiteral(`{` + strings.Replace(innerField, ".", ",", -1) + `}`)
+}
+
+// setNestedValue sets value at dot-separated path within m, creating missing parents
+func setNestedValue(m map[string]interface{}, path string, value interface{}) {
+	keys := strings.Split(path, ".")
+	for _, key := range keys[:len(keys)-1] {
+		child, ok := m[key].(map[string]interface{})
+		if !ok {
+			child = make(map[string]interface{})
+			m[key] = child
+		}
+		m = child
+	}
+
+	m[keys[len(keys)-1]] = value
+}
+
+// getNestedValue returns the value at dot-separated path within m
+func getNestedValue(m map[string]interface{}, path string) (interface{}, bool) {
+	var (
+		value interface{} = m
+		ok    bool
+	)
+
+	for _, key := range strings.Split(path, ".") {
+		child, isMap := value.(map[string]interface{})
+		if !isMap {
+			return nil, false
+		}
+		if value, ok = child[key]; !ok {
+			return nil, false
+		}
+	}
+
+	return value, true
+}
+
+// getInnerFieldAny returns the parent and inner field of a dot-separated field whatever the parent is
+func getInnerFieldAny(field string) (string, string, bool) {
+	i := strings.Index(field, ".")
+	if i == -1 {
+		return "", "", false
+	}
+
+	parent := field[:i]
+	innerField, ok := GetInnerField(parent, field)
+
+	return parent, innerField, ok
 }
@@ -0,0 +1,26 @@
+package somesql_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.lsl.digital/lardwaz/somesql"
+)
+
+func TestFields_List(t *testing.T) {
+	f := somesql.NewFields().ID("a1").Type("article").Set("data.title", "Hello").Set("path", "/hello")
+
+	fields, values := f.List()
+	assert.Equal(t, []string{"id", "type", "data"}, fields, "List defaults to the fields of TableRepo")
+	assert.Equal(t, []interface{}{"a1", "article"}, values[:2])
+
+	fields, _ = f.ListOf(somesql.TableSlugs)
+	assert.Equal(t, []string{"path"}, fields)
+
+	assert.Equal(t, somesql.TableRepo.MetaFields, somesql.MetaFieldsList)
+	assert.Equal(t, append(append([]string(nil), somesql.TableRepo.MetaFields...), somesql.TableRepo.JSONBFields...), somesql.FieldsList)
+}
+
+func TestFields_JSONBArrayActions(t *testing.T) {
+	assert.Equal(t, []uint8{7, 8, 9, 10}, []uint8{somesql.NoneJSONBArr, somesql.JSONBArrAdd, somesql.JSONBArrRemove, somesql.JSONBArrAddUnique}, "values of the actions are kept")
+}
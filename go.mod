@@ -3,6 +3,7 @@ module go.lsl.digital/lardwaz/somesql
 require (
 	github.com/kr/pretty v0.1.0 // indirect
 	github.com/lib/pq v1.2.0
+	github.com/mattn/go-sqlite3 v1.14.14
 	github.com/satori/go.uuid v1.2.0
 	github.com/stretchr/testify v1.3.0
 	gopkg.in/check.v1 v1.0.0-20180628173108-788fd7840127 // indirect
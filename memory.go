@@ -0,0 +1,296 @@
+package somesql
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	uuid "github.com/satori/go.uuid"
+)
+
+// Errors of the memory store
+var (
+	// ErrUnsupported is returned for statements or conditions the memory store cannot evaluate
+	ErrUnsupported = errors.New("not supported by memory store")
+	// ErrDuplicateKey is returned when an inserted row conflicts with an existing one on id
+	ErrDuplicateKey = errors.New("duplicate key value violates unique constraint")
+)
+
+// Memory is a store of documents held in memory
+// Statements are evaluated in Go against the rows of their table, with the semantics of the Postgres output
+// They are run against the store with Memory.Select, Memory.Insert, Memory.Update and Memory.Delete
+type Memory struct {
+	mu     sync.RWMutex
+	tables map[string][]memoryRow
+	rows   *memoryRows // results handed as *sql.Rows
+}
+
+// memoryRow represents a row of a table in memory, by column
+// JSONB columns hold decoded JSON objects
+type memoryRow map[string]interface{}
+
+// memoryDefaults holds the default values of meta columns not set on insert
+var memoryDefaults = map[string]func() interface{}{
+	FieldID:        func() interface{} { return uuid.NewV4().String() },
+	FieldCreatedAt: func() interface{} { return time.Now().UTC() },
+	FieldUpdatedAt: func() interface{} { return time.Now().UTC() },
+	FieldOwnerID:   func() interface{} { return uuid.Nil.String() },
+	FieldType:      func() interface{} { return "article" },
+}
+
+// NewMemory returns a new empty Memory
+func NewMemory() *Memory {
+	return &Memory{
+		tables: make(map[string][]memoryRow),
+		rows:   newMemoryRows(),
+	}
+}
+
+// Select returns s run against the memory store
+func (m *Memory) Select(s *Select) *MemorySelect {
+	return &MemorySelect{Select: s, memory: m}
+}
+
+// Insert returns s run against the memory store
+func (m *Memory) Insert(s *Insert) *MemoryInsert {
+	return &MemoryInsert{Insert: s, memory: m}
+}
+
+// Update returns s run against the memory store
+func (m *Memory) Update(s *Update) *MemoryUpdate {
+	return &MemoryUpdate{Update: s, memory: m}
+}
+
+// Delete returns s run against the memory store
+func (m *Memory) Delete(s *Delete) *MemoryDelete {
+	return &MemoryDelete{Delete: s, memory: m}
+}
+
+// Len returns the number of rows of table t
+func (m *Memory) Len(t Table) int {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return len(m.tables[t.Name])
+}
+
+// getColumn returns the value of field for lang, data is read across the fallback langs
+// data of a lang is skipped when empty
+func (r memoryRow) getColumn(field, lang string, fallback []string) interface{} {
+	if !(IsFieldData(field) || IsFieldRelations(field)) {
+		return r[GetLangField(field, lang)]
+	}
+
+	langs := getLangChain(lang, fallback)
+	for _, l := range langs[:len(langs)-1] {
+		if data, ok := r[GetLangFieldData(l)].(map[string]interface{}); ok && len(data) > 0 {
+			return data
+		}
+	}
+
+	return r[GetLangFieldData(langs[len(langs)-1])]
+}
+
+// getLang returns the first lang of the fallback chain having data
+func (r memoryRow) getLang(lang string, fallback []string) string {
+	langs := getLangChain(lang, fallback)
+	for _, l := range langs[:len(langs)-1] {
+		if data, ok := r[GetLangFieldData(l)].(map[string]interface{}); ok && len(data) > 0 {
+			return l
+		}
+	}
+
+	return langs[len(langs)-1]
+}
+
+// getInner returns the value of innerField of JSONB field for lang
+// inner fields of data are read from the first lang of the fallback chain having data (see getColumn)
+func (r memoryRow) getInner(field, lang string, fallback []string, innerField string) (interface{}, bool) {
+	data, ok := r.getColumn(field, lang, fallback).(map[string]interface{})
+	if !ok {
+		return nil, false
+	}
+
+	value, ok := getNestedValue(data, innerField)
+
+	return value, ok && value != nil
+}
+
+// copy returns a copy of the row, JSONB columns are copied deeply
+func (r memoryRow) copy() memoryRow {
+	row := make(memoryRow, len(r))
+	for column, value := range r {
+		if data, ok := value.(map[string]interface{}); ok {
+			value = normalizeJSON(data)
+		}
+		row[column] = value
+	}
+
+	return row
+}
+
+// normalizeJSON returns value as decoded from its JSON document, as stored in a JSONB column
+func normalizeJSON(value interface{}) interface{} {
+	var normalized interface{}
+
+	jsonBytes, err := json.Marshal(value)
+	if err != nil {
+		return nil
+	}
+	if err := json.Unmarshal(jsonBytes, &normalized); err != nil {
+		return nil
+	}
+
+	return normalized
+}
+
+// asText returns value as text, as read with ->> or sent as a parameter
+// false is returned for NULL
+func asText(value interface{}) (string, bool) {
+	switch v := value.(type) {
+	case nil:
+		return "", false
+	case string:
+		return v, true
+	case []byte:
+		return string(v), true
+	case bool:
+		return strconv.FormatBool(v), true
+	case int:
+		return strconv.Itoa(v), true
+	case int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+		return fmt.Sprint(v), true
+	case float32:
+		return strconv.FormatFloat(float64(v), 'f', -1, 32), true
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), true
+	case time.Time:
+		return v.Format(time.RFC3339Nano), true
+	case fmt.Stringer:
+		return v.String(), true
+	case map[string]interface{}, []interface{}:
+		jsonBytes, err := json.Marshal(v)
+		return string(jsonBytes), err == nil
+	}
+
+	return fmt.Sprint(value), true
+}
+
+// asFloat returns numeric value as float64
+func asFloat(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case int:
+		return float64(v), true
+	case int8:
+		return float64(v), true
+	case int16:
+		return float64(v), true
+	case int32:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case uint:
+		return float64(v), true
+	case uint8:
+		return float64(v), true
+	case uint16:
+		return float64(v), true
+	case uint32:
+		return float64(v), true
+	case uint64:
+		return float64(v), true
+	case float32:
+		return float64(v), true
+	case float64:
+		return v, true
+	}
+
+	return 0, false
+}
+
+// compareValues compares a and b, numbers and times by value and others as text
+// false is returned if any is NULL
+func compareValues(a, b interface{}) (int, bool) {
+	if a == nil || b == nil {
+		return 0, false
+	}
+
+	if x, ok := asFloat(a); ok {
+		if y, ok := asFloat(b); ok {
+			switch {
+			case x < y:
+				return -1, true
+			case x > y:
+				return 1, true
+			}
+			return 0, true
+		}
+	}
+
+	ta, isTimeA := a.(time.Time)
+	tb, isTimeB := b.(time.Time)
+	if isTimeA || isTimeB {
+		if !isTimeA {
+			ta = asTime(a)
+		}
+		if !isTimeB {
+			tb = asTime(b)
+		}
+		switch {
+		case ta.Before(tb):
+			return -1, true
+		case ta.After(tb):
+			return 1, true
+		}
+		return 0, true
+	}
+
+	if x, ok := a.(bool); ok {
+		if y, ok := b.(bool); ok {
+			switch {
+			case x == y:
+				return 0, true
+			case !x:
+				return -1, true
+			}
+			return 1, true
+		}
+	}
+
+	x, _ := asText(a)
+	y, _ := asText(b)
+
+	return strings.Compare(x, y), true
+}
+
+// sortRows sorts rows by the values of each order, NULLs are last in ascending order and first in descending order
+func sortRows(rows []memoryRow, orders []order, value func(memoryRow, string) interface{}) {
+	sort.SliceStable(rows, func(i, j int) bool {
+		for _, o := range orders {
+			a, b := value(rows[i], o.field), value(rows[j], o.field)
+
+			switch {
+			case a == nil && b == nil:
+				continue
+			case a == nil:
+				return !o.order
+			case b == nil:
+				return o.order
+			}
+
+			cmp, _ := compareValues(a, b)
+			if cmp == 0 {
+				continue
+			}
+
+			return (cmp < 0) == o.order
+		}
+
+		return false
+	})
+}
@@ -0,0 +1,587 @@
+package somesql
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// truth is the result of a condition in the three-valued logic of SQL
+// comparisons with NULL are unknown, and so is their negation
+type truth uint8
+
+// Truth values
+const (
+	truthFalse truth = iota
+	truthTrue
+	truthUnknown
+)
+
+// asTruth returns b as a truth value
+func asTruth(b bool) truth {
+	if b {
+		return truthTrue
+	}
+
+	return truthFalse
+}
+
+// and returns t AND u
+func (t truth) and(u truth) truth {
+	switch {
+	case t == truthFalse || u == truthFalse:
+		return truthFalse
+	case t == truthUnknown || u == truthUnknown:
+		return truthUnknown
+	}
+
+	return truthTrue
+}
+
+// or returns t OR u
+func (t truth) or(u truth) truth {
+	switch {
+	case t == truthTrue || u == truthTrue:
+		return truthTrue
+	case t == truthUnknown || u == truthUnknown:
+		return truthUnknown
+	}
+
+	return truthFalse
+}
+
+// not returns NOT t
+func (t truth) not() truth {
+	switch t {
+	case truthTrue:
+		return truthFalse
+	case truthFalse:
+		return truthTrue
+	}
+
+	return truthUnknown
+}
+
+// matchConditions returns true if row matches conds, rows for which conds are unknown are not matched as in SQL
+func (m *Memory) matchConditions(row memoryRow, conds []Condition) (bool, error) {
+	t, err := m.evalConditions(row, conds)
+
+	return t == truthTrue, err
+}
+
+// evalConditions returns the truth of conds for row, AND taking precedence over OR as in SQL
+func (m *Memory) evalConditions(row memoryRow, conds []Condition) (truth, error) {
+	var (
+		matched = truthFalse
+		isAnd   = truthTrue // AND of the current chain of conditions
+	)
+
+	for i, cond := range conds {
+		if i != 0 {
+			switch cond.ConditionType() {
+			case AndCondition:
+			case OrCondition:
+				matched = matched.or(isAnd)
+				isAnd = truthTrue
+			default:
+				continue
+			}
+		}
+
+		if isAnd == truthFalse { // the chain is false whatever the condition
+			continue
+		}
+
+		t, err := m.evalCondition(row, cond)
+		if err != nil {
+			return truthFalse, err
+		}
+		isAnd = isAnd.and(t)
+	}
+
+	return matched.or(isAnd), nil
+}
+
+// evalCondition returns the truth of cond for row
+func (m *Memory) evalCondition(row memoryRow, cond Condition) (truth, error) {
+	switch c := cond.(type) {
+	case ConditionClause:
+		return matchClause(row, c)
+	case ConditionIn:
+		return matchIn(row, c)
+	case ConditionGroup:
+		t, err := m.evalConditions(row, c.Conditions)
+		if c.Not {
+			t = t.not()
+		}
+		return t, err
+	case ConditionQuery:
+		return m.matchQuery(row, c)
+	}
+
+	return truthFalse, fmt.Errorf("%w: condition %T", ErrUnsupported, cond)
+}
+
+// matchClause evaluates a ConditionClause against row
+func matchClause(row memoryRow, c ConditionClause) (truth, error) {
+	var (
+		lhs      interface{}
+		fallback = c.ctx.fallback
+		operator = normalizeOperator(c.Operator)
+	)
+
+	switch operator {
+	case OpHasKey, OpHasAnyKey, OpHasAllKeys, OpContains:
+		return matchJSON(row, c, operator), nil
+	}
+
+	if _, ok := c.Value.(Reference); ok {
+		return truthFalse, fmt.Errorf("%w: references to the fields of an outer statement", ErrUnsupported)
+	}
+
+	if !strings.Contains(c.Field, ".") {
+		lhs = row.getColumn(c.Field, c.Lang, fallback)
+	} else if innerField, ok := GetInnerField(FieldData, c.Field); ok {
+		lhs = getInnerText(row, FieldData, c.Lang, fallback, innerField)
+	} else if innerField, ok := GetInnerField(FieldRelations, c.Field); ok {
+		if operator == OpIsNull || operator == OpIsNotNull {
+			lhs = getInnerText(row, FieldRelations, c.Lang, fallback, innerField)
+			return asTruth((lhs == nil) == (operator == OpIsNull)), nil
+		}
+
+		// relations are arrays, the value is matched against their elements (jsonb_path_exists is never NULL)
+		value, _ := asText(c.Value)
+		for _, elem := range getArray(row, FieldRelations, c.Lang, fallback, innerField) {
+			if s, ok := elem.(string); ok && s == value {
+				return truthTrue, nil
+			}
+		}
+		return truthFalse, nil
+	} else if parent, innerField, ok := getInnerFieldAny(c.Field); ok {
+		lhs = getInnerText(row, parent, c.Lang, nil, innerField)
+	}
+
+	lhs, err := applyFunction(c.FieldFunction, lhs)
+	if err != nil {
+		return truthFalse, err
+	}
+
+	switch operator {
+	case OpIsNull, OpIsNotNull:
+		return asTruth((lhs == nil) == (operator == OpIsNull)), nil
+	case OpBetween, OpNotBetween:
+		return matchBetween(lhs, c, operator)
+	}
+
+	if _, ok := c.Value.(bool); ok {
+		lhs = asBool(lhs)
+	}
+
+	rhs, err := applyFunction(c.ValueFunction, c.Value)
+	if err != nil {
+		return truthFalse, err
+	}
+
+	return compareOperator(c.Operator, lhs, rhs)
+}
+
+// matchBetween returns the result of lhs BETWEEN (or NOT BETWEEN) the bounds of c, unknown if any is NULL
+func matchBetween(lhs interface{}, c ConditionClause, operator string) (truth, error) {
+	bounds := c.values(operator)
+	if len(bounds) != 2 {
+		return truthUnknown, nil
+	}
+
+	if text, ok := lhs.(string); ok && strings.Contains(c.Field, ".") && isNumeric(bounds) { // as cast to NUMERIC
+		number, err := strconv.ParseFloat(text, 64)
+		if err != nil {
+			return truthFalse, fmt.Errorf("invalid input syntax for type numeric: %q", text)
+		}
+		lhs = number
+	}
+
+	from, err := applyFunction(c.ValueFunction, bounds[0])
+	if err != nil {
+		return truthFalse, err
+	}
+	to, err := applyFunction(c.ValueFunction, bounds[1])
+	if err != nil {
+		return truthFalse, err
+	}
+
+	cmpFrom, okFrom := compareValues(lhs, from)
+	cmpTo, okTo := compareValues(lhs, to)
+	if !okFrom || !okTo {
+		return truthUnknown, nil
+	}
+
+	return asTruth((cmpFrom >= 0 && cmpTo <= 0) != (operator == OpNotBetween)), nil
+}
+
+// matchJSON evaluates key existence and containment of a ConditionClause against row, as with JSONB
+// the result is unknown if the document is NULL
+func matchJSON(row memoryRow, c ConditionClause, operator string) truth {
+	var (
+		doc      interface{}
+		fallback = c.ctx.fallback
+	)
+
+	if innerField, ok := GetInnerField(FieldData, c.Field); ok {
+		doc, _ = row.getInner(FieldData, c.Lang, fallback, innerField)
+	} else if innerField, ok := GetInnerField(FieldRelations, c.Field); ok {
+		doc, _ = row.getInner(FieldRelations, c.Lang, fallback, innerField)
+	} else if parent, innerField, ok := getInnerFieldAny(c.Field); ok {
+		doc, _ = row.getInner(parent, c.Lang, nil, innerField)
+	} else {
+		doc = row.getColumn(c.Field, c.Lang, fallback)
+	}
+
+	if doc == nil {
+		return truthUnknown
+	}
+
+	if operator == OpContains {
+		value := normalizeJSON(c.Value)
+		if elems, ok := doc.([]interface{}); ok && isJSONScalar(value) { // arrays contain their scalar elements
+			return asTruth(containsJSON(elems, value))
+		}
+		return asTruth(containsJSONValue(doc, value))
+	}
+
+	var (
+		keys  = asSlice(c.Value)
+		found int
+	)
+
+	for _, k := range keys {
+		key, _ := asText(k)
+		if hasJSONKey(doc, key) {
+			found++
+		}
+	}
+
+	if operator == OpHasAllKeys {
+		return asTruth(found == len(keys))
+	}
+
+	return asTruth(found > 0)
+}
+
+// hasJSONKey returns true if doc has key, as the ? operator of JSONB
+// keys of objects, string elements of arrays and strings are matched
+func hasJSONKey(doc interface{}, key string) bool {
+	switch d := doc.(type) {
+	case map[string]interface{}:
+		_, ok := d[key]
+		return ok
+	case []interface{}:
+		for _, elem := range d {
+			if s, ok := elem.(string); ok && s == key {
+				return true
+			}
+		}
+	case string:
+		return d == key
+	}
+
+	return false
+}
+
+// containsJSONValue returns true if JSON doc contains value, as the @> operator of JSONB
+// objects contain the pairs of value, arrays contain the elements of value
+func containsJSONValue(doc, value interface{}) bool {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		object, ok := doc.(map[string]interface{})
+		if !ok {
+			return false
+		}
+		for key, child := range v {
+			if elem, ok := object[key]; !ok || !containsJSONValue(elem, child) {
+				return false
+			}
+		}
+		return true
+	case []interface{}:
+		array, ok := doc.([]interface{})
+		if !ok {
+			return false
+		}
+		for _, child := range v {
+			var found bool
+			for _, elem := range array {
+				if containsJSONValue(elem, child) {
+					found = true
+					break
+				}
+			}
+			if !found {
+				return false
+			}
+		}
+		return true
+	}
+
+	return isJSONScalar(doc) && containsJSON([]interface{}{doc}, value)
+}
+
+// isJSONScalar returns true if value is neither a JSON object nor an array
+func isJSONScalar(value interface{}) bool {
+	switch value.(type) {
+	case map[string]interface{}, []interface{}:
+		return false
+	}
+
+	return true
+}
+
+// matchIn evaluates a ConditionIn against row, unknown if a meta field is NULL
+// inner fields are arrays having any of the values, elements being compared as text
+func matchIn(row memoryRow, c ConditionIn) (truth, error) {
+	var (
+		vals, _ = expandValues(c.Values)
+		isNot   = c.Operator == "NOT IN"
+	)
+
+	if !strings.Contains(c.Field, ".") {
+		lhs, err := applyFunction(c.FieldFunction, row[c.Field])
+		if err != nil {
+			return truthFalse, err
+		}
+
+		return matchList(lhs, vals, isNot), nil
+	}
+
+	var elems []interface{}
+	if innerField, ok := GetInnerField(FieldData, c.Field); ok {
+		elems = getArray(row, FieldData, c.Lang, c.ctx.fallback, innerField)
+	} else if innerField, ok := GetInnerField(FieldRelations, c.Field); ok {
+		elems = getArray(row, FieldRelations, c.Lang, c.ctx.fallback, innerField)
+	} else if parent, innerField, ok := getInnerFieldAny(c.Field); ok {
+		elems = getArray(row, parent, c.Lang, nil, innerField)
+	}
+
+	for _, elem := range elems {
+		text, ok := asText(elem)
+		if !ok {
+			continue
+		}
+		for _, v := range vals {
+			if t, _ := asText(v); t == text {
+				return asTruth(!isNot), nil
+			}
+		}
+	}
+
+	return asTruth(isNot), nil
+}
+
+// matchList returns the result of lhs IN (or NOT IN) vals
+// it is unknown if lhs is NULL, or if none matches and any of vals is NULL
+func matchList(lhs interface{}, vals []interface{}, isNot bool) truth {
+	if lhs == nil {
+		return truthUnknown
+	}
+
+	result := asTruth(isNot)
+	for _, v := range vals {
+		if v == nil {
+			result = truthUnknown
+		} else if cmp, ok := compareValues(lhs, v); ok && cmp == 0 {
+			return asTruth(!isNot)
+		}
+	}
+
+	return result
+}
+
+// matchQuery evaluates a ConditionQuery against row, the sub-query is run against the store
+func (m *Memory) matchQuery(row memoryRow, c ConditionQuery) (truth, error) {
+	var lhs interface{}
+
+	if c.isExists() {
+		s, err := subSelect(c.Query)
+		if err != nil {
+			return truthFalse, err
+		}
+
+		rows, err := m.selectRows(*s)
+		if err != nil {
+			return truthFalse, err
+		}
+
+		return asTruth((len(rows) > 0) != (c.Operator == "NOT EXISTS")), nil
+	}
+
+	if IsFieldMeta(c.Field) || IsFieldData(c.Field) || IsFieldRelations(c.Field) {
+		lhs = row[c.Field]
+	} else if innerField, ok := GetInnerField(FieldData, c.Field); ok {
+		lhs = getInnerText(row, FieldData, c.Lang, c.ctx.fallback, innerField)
+	} else {
+		lhs = getInnerText(row, FieldData, c.Lang, c.ctx.fallback, c.Field)
+	}
+
+	values, err := m.queryValues(c.Query)
+	if err != nil {
+		return truthFalse, err
+	}
+
+	return matchList(lhs, values, c.Operator == "NOT IN"), nil
+}
+
+// getInnerText returns innerField of JSONB field as text (->>), nil if NULL
+func getInnerText(row memoryRow, field, lang string, fallback []string, innerField string) interface{} {
+	value, ok := row.getInner(field, lang, fallback, innerField)
+	if !ok {
+		return nil
+	}
+
+	text, _ := asText(value)
+
+	return text
+}
+
+// getArray returns the elements of array innerField of JSONB field, nil if not an array
+// data is read from the first language of the fallback chain having any
+func getArray(row memoryRow, field, lang string, fallback []string, innerField string) []interface{} {
+	data, ok := row.getColumn(field, lang, fallback).(map[string]interface{})
+	if !ok {
+		return nil
+	}
+
+	value, _ := getNestedValue(data, innerField)
+	elems, _ := value.([]interface{})
+
+	return elems
+}
+
+// asBool returns value cast to BOOLEAN, nil if it is not a boolean
+func asBool(value interface{}) interface{} {
+	switch v := value.(type) {
+	case bool:
+		return v
+	case string:
+		switch strings.ToLower(v) {
+		case "true", "t":
+			return true
+		case "false", "f":
+			return false
+		}
+	}
+
+	return nil
+}
+
+// applyFunction returns value with SQL function applied (None for no function)
+func applyFunction(function string, value interface{}) (interface{}, error) {
+	if function == None {
+		return value, nil
+	}
+
+	text, ok := asText(value)
+	if !ok {
+		return nil, nil
+	}
+
+	switch strings.ToUpper(function) {
+	case "LOWER":
+		return strings.ToLower(text), nil
+	case "UPPER":
+		return strings.ToUpper(text), nil
+	case "TRIM":
+		return strings.TrimSpace(text), nil
+	case "LENGTH":
+		return len([]rune(text)), nil
+	}
+
+	return nil, fmt.Errorf("%w: function %s", ErrUnsupported, function)
+}
+
+// compareOperator returns the result of lhs operator rhs, unknown if any is NULL
+func compareOperator(operator string, lhs, rhs interface{}) (truth, error) {
+	operator = normalizeOperator(operator)
+
+	switch operator {
+	case OpMatch, OpIMatch, OpNotMatch, OpNotIMatch:
+		text, isText := asText(lhs)
+		pattern, isPattern := asText(rhs)
+		if !isText || !isPattern {
+			return truthUnknown, nil
+		}
+
+		if strings.HasSuffix(operator, "*") {
+			pattern = `(?i)` + pattern
+		}
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return truthFalse, fmt.Errorf("invalid regular expression: %w", err)
+		}
+
+		return asTruth(re.MatchString(text) != strings.HasPrefix(operator, "!")), nil
+	case OpLike, OpNotLike, OpILike, OpNotILike:
+		text, isText := asText(lhs)
+		pattern, isPattern := asText(rhs)
+		if !isText || !isPattern {
+			return truthUnknown, nil
+		}
+
+		matched := likeRegexp(pattern, strings.HasSuffix(operator, "ILIKE")).MatchString(text)
+
+		return asTruth(matched != strings.HasPrefix(operator, "NOT")), nil
+	}
+
+	cmp, ok := compareValues(lhs, rhs)
+	if !ok {
+		return truthUnknown, nil
+	}
+
+	switch operator {
+	case "=":
+		return asTruth(cmp == 0), nil
+	case "<>", "!=":
+		return asTruth(cmp != 0), nil
+	case "<":
+		return asTruth(cmp < 0), nil
+	case "<=":
+		return asTruth(cmp <= 0), nil
+	case ">":
+		return asTruth(cmp > 0), nil
+	case ">=":
+		return asTruth(cmp >= 0), nil
+	}
+
+	return truthFalse, fmt.Errorf("%w: operator %s", ErrUnsupported, operator)
+}
+
+// likeRegexp returns the regular expression of a LIKE pattern, % and _ being wildcards escaped with \
+func likeRegexp(pattern string, caseInsensitive bool) *regexp.Regexp {
+	var (
+		buff    strings.Builder
+		escaped bool
+	)
+
+	if caseInsensitive {
+		buff.WriteString(`(?i)`)
+	}
+	buff.WriteString(`(?s)^`)
+
+	for _, r := range pattern {
+		switch {
+		case escaped:
+			buff.WriteString(regexp.QuoteMeta(string(r)))
+			escaped = false
+		case r == '\\':
+			escaped = true
+		case r == '%':
+			buff.WriteString(`.*`)
+		case r == '_':
+			buff.WriteString(`.`)
+		default:
+			buff.WriteString(regexp.QuoteMeta(string(r)))
+		}
+	}
+	buff.WriteString(`$`)
+
+	return regexp.MustCompile(buff.String())
+}
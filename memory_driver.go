@@ -0,0 +1,135 @@
+package somesql
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"strconv"
+	"sync"
+)
+
+// memoryRows hands the results of the memory store to database/sql as *sql.Rows
+// results are registered under a key which is then queried with the memory driver
+type memoryRows struct {
+	mu      sync.Mutex
+	next    int
+	results map[string]*memoryResult
+	db      *sql.DB
+}
+
+// memoryResult represents the columns and values of rows computed by the memory store
+type memoryResult struct {
+	columns []string
+	values  [][]driver.Value
+}
+
+func newMemoryRows() *memoryRows {
+	r := &memoryRows{results: make(map[string]*memoryResult)}
+	r.db = sql.OpenDB(memoryConnector{rows: r})
+
+	return r
+}
+
+// query returns result as *sql.Rows
+func (r *memoryRows) query(ctx context.Context, result *memoryResult) (*sql.Rows, error) {
+	r.mu.Lock()
+	r.next++
+	key := strconv.Itoa(r.next)
+	r.results[key] = result
+	r.mu.Unlock()
+
+	rows, err := r.db.QueryContext(ctx, key)
+	if err != nil {
+		r.take(key)
+		return nil, err
+	}
+
+	return rows, nil
+}
+
+// take returns and unregisters the result of key
+func (r *memoryRows) take(key string) (*memoryResult, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	result, ok := r.results[key]
+	delete(r.results, key)
+
+	return result, ok
+}
+
+// memoryConnector implements driver.Connector
+type memoryConnector struct {
+	rows *memoryRows
+}
+
+func (c memoryConnector) Connect(context.Context) (driver.Conn, error) {
+	return memoryConn(c), nil
+}
+
+func (c memoryConnector) Driver() driver.Driver {
+	return memoryDriver(c)
+}
+
+// memoryDriver implements driver.Driver
+type memoryDriver struct {
+	rows *memoryRows
+}
+
+func (d memoryDriver) Open(string) (driver.Conn, error) {
+	return memoryConn(d), nil
+}
+
+// memoryConn implements driver.Conn and driver.QueryerContext
+// queries are the keys of registered results, which are read once
+type memoryConn struct {
+	rows *memoryRows
+}
+
+func (c memoryConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	result, ok := c.rows.take(query)
+	if !ok {
+		return nil, errors.New("memory store: unknown result " + query)
+	}
+
+	return &memoryDriverRows{result: result}, nil
+}
+
+func (c memoryConn) Prepare(string) (driver.Stmt, error) {
+	return nil, ErrUnsupported
+}
+
+func (c memoryConn) Close() error {
+	return nil
+}
+
+func (c memoryConn) Begin() (driver.Tx, error) {
+	return nil, ErrUnsupported
+}
+
+// memoryDriverRows implements driver.Rows
+type memoryDriverRows struct {
+	result *memoryResult
+	next   int
+}
+
+func (r *memoryDriverRows) Columns() []string {
+	return r.result.columns
+}
+
+func (r *memoryDriverRows) Close() error {
+	return nil
+}
+
+func (r *memoryDriverRows) Next(dest []driver.Value) error {
+	if r.next >= len(r.result.values) {
+		return io.EOF
+	}
+
+	copy(dest, r.result.values[r.next])
+	r.next++
+
+	return nil
+}
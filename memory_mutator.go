@@ -0,0 +1,652 @@
+package somesql
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// MemoryInsert is an Insert run against a Memory store
+// Implements: Mutator, Returner
+// Transactions are not supported: statements are applied immediately, ErrUnsupported is returned within tx or without autocommit
+type MemoryInsert struct {
+	*Insert
+	memory *Memory
+}
+
+// MemoryUpdate is an Update run against a Memory store
+// Implements: Mutator, Returner
+// Transactions are not supported: statements are applied immediately, ErrUnsupported is returned within tx or without autocommit
+type MemoryUpdate struct {
+	*Update
+	memory *Memory
+}
+
+// MemoryDelete is a Delete run against a Memory store
+// Implements: Mutator, Returner
+// Transactions are not supported: statements are applied immediately, ErrUnsupported is returned within tx or without autocommit
+type MemoryDelete struct {
+	*Delete
+	memory *Memory
+}
+
+// errMemoryTx is returned for statements run within a transaction
+var errMemoryTx = fmt.Errorf("%w: transactions", ErrUnsupported)
+
+// checkAutocommit returns ErrUnsupported for statements which are not committed
+func checkAutocommit(autocommit bool) error {
+	if !autocommit {
+		return fmt.Errorf("%w: statements without autocommit", ErrUnsupported)
+	}
+
+	return nil
+}
+
+// memoryMutation applies a statement to the rows of its table
+// it returns the new rows of the table and the rows affected
+type memoryMutation func(rows []memoryRow) ([]memoryRow, []memoryRow, error)
+
+// mutate applies mutation to the rows of table t, rows are left untouched on error
+func (m *Memory) mutate(t Table, mutation memoryMutation) ([]memoryRow, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	rows := make([]memoryRow, len(m.tables[t.Name]))
+	for i, row := range m.tables[t.Name] {
+		rows[i] = row.copy()
+	}
+
+	rows, affected, err := mutation(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	m.tables[t.Name] = rows
+
+	return affected, nil
+}
+
+// returning returns the documents of the returning fields of rows
+func (m *Memory) returning(ctx context.Context, table Table, lang string, fields []string, rows []memoryRow) ([]Document, error) {
+	docs := make([]Document, 0)
+
+	result, err := m.returningRows(ctx, table, lang, fields, rows)
+	if err != nil {
+		return nil, err
+	}
+
+	err = scanDocuments(result, lang, func(d Document) error {
+		docs = append(docs, d)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return docs, nil
+}
+
+// returningRows returns the returning fields of rows as *sql.Rows
+func (m *Memory) returningRows(ctx context.Context, table Table, lang string, fields []string, rows []memoryRow) (*sql.Rows, error) {
+	result := &memoryResult{}
+	if len(fields) > 0 {
+		result = projectRows(table, lang, fields, false, rows)
+	}
+
+	return m.rows.query(ctx, result)
+}
+
+// Exec implements Mutator
+func (s MemoryInsert) Exec(autocommit bool) error {
+	if err := checkAutocommit(autocommit); err != nil {
+		return err
+	}
+
+	_, err := s.memory.insert(*s.Insert)
+	return err
+}
+
+// ExecContext implements Mutator
+func (s MemoryInsert) ExecContext(ctx context.Context, autocommit bool) error {
+	return s.Exec(autocommit)
+}
+
+// ExecTx implements Mutator
+func (s MemoryInsert) ExecTx(tx *sql.Tx, autocommit bool) error {
+	return errMemoryTx
+}
+
+// ExecTxContext implements Mutator
+func (s MemoryInsert) ExecTxContext(ctx context.Context, tx *sql.Tx, autocommit bool) error {
+	return errMemoryTx
+}
+
+// ExecReturning implements Returner
+// Fields of the table are returned unless set with Returning
+func (s MemoryInsert) ExecReturning(autocommit bool) ([]Document, error) {
+	return s.ExecReturningContext(context.Background(), autocommit)
+}
+
+// ExecReturningContext implements Returner
+// Fields of the table are returned unless set with Returning
+func (s MemoryInsert) ExecReturningContext(ctx context.Context, autocommit bool) ([]Document, error) {
+	if err := checkAutocommit(autocommit); err != nil {
+		return nil, err
+	}
+
+	insert := *s.Insert
+	insert.useDefaultReturning()
+
+	rows, err := s.memory.insert(insert)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.memory.returning(ctx, insert.GetTable(), insert.GetLang(), insert.returning, rows)
+}
+
+// ExecTxReturning implements Returner
+func (s MemoryInsert) ExecTxReturning(tx *sql.Tx, autocommit bool) ([]Document, error) {
+	return nil, errMemoryTx
+}
+
+// ExecTxReturningContext implements Returner
+func (s MemoryInsert) ExecTxReturningContext(ctx context.Context, tx *sql.Tx, autocommit bool) ([]Document, error) {
+	return nil, errMemoryTx
+}
+
+// RowsTx implements Returner
+func (s MemoryInsert) RowsTx(tx *sql.Tx) (*sql.Rows, error) {
+	return s.RowsTxContext(context.Background(), tx)
+}
+
+// RowsTxContext implements Returner
+func (s MemoryInsert) RowsTxContext(ctx context.Context, tx *sql.Tx) (*sql.Rows, error) {
+	return nil, errMemoryTx
+}
+
+// insert applies s to the store, it returns the rows inserted or updated on conflict
+// errors of the builder are returned before the store is touched
+func (m *Memory) insert(s Insert) ([]memoryRow, error) {
+	if _, _, err := s.Build(); err != nil {
+		return nil, err
+	}
+
+	var (
+		table   = s.GetTable()
+		lang    = s.GetLang()
+		rows    = s.fields
+		columns = s.columns(rows)
+	)
+
+	conflictFields := s.conflictFields
+	if s.conflict == ConflictNone && table.IsMeta(FieldID) {
+		conflictFields = []string{FieldID}
+	}
+
+	return m.mutate(table, func(tableRows []memoryRow) ([]memoryRow, []memoryRow, error) {
+		var affected []memoryRow
+
+		for _, fields := range rows {
+			row := newMemoryRow(table, lang)
+
+			names, values := fields.ListOf(table)
+			for i, f := range names {
+				if jsonbFields, ok := values[i].(JSONBFields); ok && table.IsJSONB(f) {
+					row[GetLangField(f, lang)] = normalizeJSON(jsonbFields.Values())
+					continue
+				}
+				row[GetLangField(f, lang)] = values[i]
+			}
+
+			existing := findConflict(tableRows, row, lang, conflictFields)
+			if existing == nil {
+				tableRows = append(tableRows, row)
+				affected = append(affected, row)
+				continue
+			}
+
+			switch s.conflict {
+			case ConflictNone:
+				return nil, nil, ErrDuplicateKey
+			case ConflictDoNothing:
+				continue
+			}
+
+			for _, f := range columns {
+				column := GetLangField(f, lang)
+				if isTarget(f, conflictFields) {
+					continue
+				}
+
+				current, isObject := existing[column].(map[string]interface{})
+				patch, isPatch := row[column].(map[string]interface{})
+				if s.conflict == ConflictMerge && table.IsJSONB(f) && isObject && isPatch {
+					for key, value := range patch {
+						current[key] = value
+					}
+					continue
+				}
+				existing[column] = row[column]
+			}
+			affected = append(affected, existing)
+		}
+
+		return tableRows, affected, nil
+	})
+}
+
+// newMemoryRow returns a row of table with the default values of its columns
+func newMemoryRow(table Table, lang string) memoryRow {
+	row := make(memoryRow)
+
+	for _, f := range table.MetaFields {
+		row[f] = nil
+		if value, ok := memoryDefaults[f]; ok {
+			row[f] = value()
+		}
+	}
+
+	for _, f := range table.JSONBFields {
+		if !IsFieldData(f) {
+			row[f] = make(map[string]interface{})
+			continue
+		}
+
+		for _, l := range getLangChain(lang, table.Langs) {
+			row[GetLangFieldData(l)] = make(map[string]interface{})
+		}
+	}
+
+	return row
+}
+
+// findConflict returns the row of rows having the same values of fields as row, nil if none
+func findConflict(rows []memoryRow, row memoryRow, lang string, fields []string) memoryRow {
+	if len(fields) == 0 {
+		return nil
+	}
+
+	for _, r := range rows {
+		isConflict := true
+		for _, f := range fields {
+			column := GetLangField(f, lang)
+			if cmp, ok := compareValues(r[column], row[column]); !ok || cmp != 0 {
+				isConflict = false
+				break
+			}
+		}
+
+		if isConflict {
+			return r
+		}
+	}
+
+	return nil
+}
+
+// isTarget returns true if f is one of the conflict fields
+func isTarget(f string, fields []string) bool {
+	for _, target := range fields {
+		if target == f {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Exec implements Mutator
+func (s MemoryUpdate) Exec(autocommit bool) error {
+	if err := checkAutocommit(autocommit); err != nil {
+		return err
+	}
+
+	_, err := s.memory.update(*s.Update)
+	return err
+}
+
+// ExecContext implements Mutator
+func (s MemoryUpdate) ExecContext(ctx context.Context, autocommit bool) error {
+	return s.Exec(autocommit)
+}
+
+// ExecTx implements Mutator
+func (s MemoryUpdate) ExecTx(tx *sql.Tx, autocommit bool) error {
+	return errMemoryTx
+}
+
+// ExecTxContext implements Mutator
+func (s MemoryUpdate) ExecTxContext(ctx context.Context, tx *sql.Tx, autocommit bool) error {
+	return errMemoryTx
+}
+
+// ExecReturning implements Returner
+// Fields of the table are returned unless set with Returning
+func (s MemoryUpdate) ExecReturning(autocommit bool) ([]Document, error) {
+	return s.ExecReturningContext(context.Background(), autocommit)
+}
+
+// ExecReturningContext implements Returner
+// Fields of the table are returned unless set with Returning
+func (s MemoryUpdate) ExecReturningContext(ctx context.Context, autocommit bool) ([]Document, error) {
+	if err := checkAutocommit(autocommit); err != nil {
+		return nil, err
+	}
+
+	update := *s.Update
+	update.useDefaultReturning()
+
+	rows, err := s.memory.update(update)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.memory.returning(ctx, update.GetTable(), update.GetLang(), update.returning, rows)
+}
+
+// ExecTxReturning implements Returner
+func (s MemoryUpdate) ExecTxReturning(tx *sql.Tx, autocommit bool) ([]Document, error) {
+	return nil, errMemoryTx
+}
+
+// ExecTxReturningContext implements Returner
+func (s MemoryUpdate) ExecTxReturningContext(ctx context.Context, tx *sql.Tx, autocommit bool) ([]Document, error) {
+	return nil, errMemoryTx
+}
+
+// RowsTx implements Returner
+func (s MemoryUpdate) RowsTx(tx *sql.Tx) (*sql.Rows, error) {
+	return s.RowsTxContext(context.Background(), tx)
+}
+
+// RowsTxContext implements Returner
+func (s MemoryUpdate) RowsTxContext(ctx context.Context, tx *sql.Tx) (*sql.Rows, error) {
+	return nil, errMemoryTx
+}
+
+// update applies s to the store, it returns the rows updated
+// errors of the builder are returned before the store is touched
+func (m *Memory) update(s Update) ([]memoryRow, error) {
+	if _, _, err := s.Build(); err != nil {
+		return nil, err
+	}
+
+	var (
+		table          = s.GetTable()
+		conds          = withContext(s.conditions, conditionContext{})
+		fields, values = s.fields.ListOf(table)
+	)
+
+	return m.mutate(table, func(rows []memoryRow) ([]memoryRow, []memoryRow, error) {
+		var affected []memoryRow
+
+		for _, row := range rows {
+			ok, err := m.matchConditions(row, conds)
+			if err != nil {
+				return nil, nil, err
+			}
+			if !ok {
+				continue
+			}
+
+			for i, f := range fields {
+				if table.IsJSONB(f) && !IsFieldRelations(f) {
+					if jsonbFields, ok := values[i].(JSONBFields); ok {
+						column := GetLangField(f, s.GetLang())
+						current, _ := row[column].(map[string]interface{})
+						row[column] = s.updateJSONB(current, jsonbFields)
+					}
+				} else if table.IsMeta(f) {
+					row[f] = values[i]
+				}
+			}
+			affected = append(affected, row)
+		}
+
+		return rows, affected, nil
+	})
+}
+
+// updateJSONB returns JSON object current with jsonbFields set (see ToSQL)
+// top-level keys are patched first, then nested fields and arrays are set in order
+func (s Update) updateJSONB(current map[string]interface{}, jsonbFields JSONBFields) map[string]interface{} {
+	var (
+		object                            = make(map[string]interface{})
+		ensured                           = make(map[string]bool)
+		innerFields, innerValues, actions = jsonbFields.GetOrderedList()
+	)
+
+	if !s.replace {
+		for key, value := range current {
+			object[key] = normalizeJSON(value) // current is read by the paths set below
+		}
+	}
+
+	for idx, innerField := range innerFields {
+		isArrayAction := actions[idx] != NoneJSONBArr && !s.replace
+		if !isArrayAction && !strings.Contains(innerField, ".") {
+			object[innerField] = updateValue(innerValues[idx])
+		}
+	}
+
+	for idx, innerField := range innerFields {
+		isArrayAction := actions[idx] != NoneJSONBArr && !s.replace
+		if !isArrayAction && !strings.Contains(innerField, ".") {
+			continue
+		}
+
+		// Missing parents of nested fields are created first
+		for _, parent := range getParentPaths(innerField) {
+			if ensured[parent] {
+				continue
+			}
+			ensured[parent] = true
+
+			// parents are read from the stored value, as all SET expressions of Postgres are
+			if value, ok := getNestedValue(current, parent); !ok || value == nil || s.replace {
+				setJSONPath(object, parent, make(map[string]interface{}))
+			} else {
+				setJSONPath(object, parent, normalizeJSON(value))
+			}
+		}
+
+		if isArrayAction {
+			stored, _ := getNestedValue(current, innerField)
+			setJSONPath(object, innerField, arrayAction(stored, normalizeJSON(asSlice(innerValues[idx])), actions[idx]))
+		} else {
+			setJSONPath(object, innerField, updateValue(innerValues[idx]))
+		}
+	}
+
+	return object
+}
+
+// updateValue returns value as set in a JSON object by Update, typed after getSQLType
+func updateValue(value interface{}) interface{} {
+	if _, ok := value.([]interface{}); ok {
+		return normalizeJSON(value)
+	}
+
+	switch getSQLType(value) {
+	case "INT":
+		return normalizeJSON(value)
+	case "BOOLEAN":
+		return value
+	}
+
+	text, _ := asText(value)
+
+	return text
+}
+
+// setJSONPath sets value at path within object if its parent is an object (jsonb_set)
+func setJSONPath(object map[string]interface{}, path string, value interface{}) {
+	keys := strings.Split(path, ".")
+	for _, key := range keys[:len(keys)-1] {
+		child, ok := object[key].(map[string]interface{})
+		if !ok {
+			return
+		}
+		object = child
+	}
+
+	object[keys[len(keys)-1]] = value
+}
+
+// arrayAction returns the elements of JSON array current after action with elements
+func arrayAction(current, elements interface{}, action uint8) []interface{} {
+	var (
+		array, _ = current.([]interface{})
+		elems, _ = elements.([]interface{})
+		result   = make([]interface{}, 0, len(array)+len(elems))
+	)
+
+	if current != nil && array == nil { // scalars are concatenated as arrays
+		array = []interface{}{current}
+	}
+
+	switch action {
+	case JSONBArrAddUnique:
+		result = append(result, array...)
+		for _, elem := range elems {
+			if !containsJSON(result, elem) {
+				result = append(result, elem)
+			}
+		}
+	case JSONBArrRemove:
+		for _, elem := range array {
+			if !containsJSON(elems, elem) {
+				result = append(result, elem)
+			}
+		}
+	default:
+		result = append(append(result, array...), elems...)
+	}
+
+	return result
+}
+
+// containsJSON returns true if any of values is equal to value as JSON
+func containsJSON(values []interface{}, value interface{}) bool {
+	valueBytes, _ := json.Marshal(value)
+	for _, v := range values {
+		if vBytes, _ := json.Marshal(v); string(vBytes) == string(valueBytes) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Exec implements Mutator
+func (s MemoryDelete) Exec(autocommit bool) error {
+	if err := checkAutocommit(autocommit); err != nil {
+		return err
+	}
+
+	_, err := s.memory.delete(*s.Delete)
+	return err
+}
+
+// ExecContext implements Mutator
+func (s MemoryDelete) ExecContext(ctx context.Context, autocommit bool) error {
+	return s.Exec(autocommit)
+}
+
+// ExecTx implements Mutator
+func (s MemoryDelete) ExecTx(tx *sql.Tx, autocommit bool) error {
+	return errMemoryTx
+}
+
+// ExecTxContext implements Mutator
+func (s MemoryDelete) ExecTxContext(ctx context.Context, tx *sql.Tx, autocommit bool) error {
+	return errMemoryTx
+}
+
+// ExecReturning implements Returner
+// Fields of the table are returned unless set with Returning
+func (s MemoryDelete) ExecReturning(autocommit bool) ([]Document, error) {
+	return s.ExecReturningContext(context.Background(), autocommit)
+}
+
+// ExecReturningContext implements Returner
+// Fields of the table are returned unless set with Returning
+func (s MemoryDelete) ExecReturningContext(ctx context.Context, autocommit bool) ([]Document, error) {
+	if err := checkAutocommit(autocommit); err != nil {
+		return nil, err
+	}
+
+	del := *s.Delete
+	del.useDefaultReturning()
+
+	rows, err := s.memory.delete(del)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.memory.returning(ctx, del.GetTable(), del.GetLang(), del.returning, rows)
+}
+
+// ExecTxReturning implements Returner
+func (s MemoryDelete) ExecTxReturning(tx *sql.Tx, autocommit bool) ([]Document, error) {
+	return nil, errMemoryTx
+}
+
+// ExecTxReturningContext implements Returner
+func (s MemoryDelete) ExecTxReturningContext(ctx context.Context, tx *sql.Tx, autocommit bool) ([]Document, error) {
+	return nil, errMemoryTx
+}
+
+// RowsTx implements Returner
+func (s MemoryDelete) RowsTx(tx *sql.Tx) (*sql.Rows, error) {
+	return s.RowsTxContext(context.Background(), tx)
+}
+
+// RowsTxContext implements Returner
+func (s MemoryDelete) RowsTxContext(ctx context.Context, tx *sql.Tx) (*sql.Rows, error) {
+	return nil, errMemoryTx
+}
+
+// delete applies s to the store, it returns the rows deleted
+// rows are deleted in the order they were inserted, after offset and up to limit if any
+// errors of the builder are returned before the store is touched
+func (m *Memory) delete(s Delete) ([]memoryRow, error) {
+	if _, _, err := s.Build(); err != nil {
+		return nil, err
+	}
+
+	var (
+		table = s.GetTable()
+		conds = withContext(s.conditions, conditionContext{})
+	)
+
+	return m.mutate(table, func(rows []memoryRow) ([]memoryRow, []memoryRow, error) {
+		var (
+			kept    []memoryRow
+			deleted []memoryRow
+			matched int
+		)
+
+		for _, row := range rows {
+			ok, err := m.matchConditions(row, conds)
+			if err != nil {
+				return nil, nil, err
+			}
+
+			if ok {
+				matched++
+				isDeleted := matched > s.offset && (s.limit <= 0 || len(deleted) < s.limit)
+				if isDeleted {
+					deleted = append(deleted, row)
+					continue
+				}
+			}
+			kept = append(kept, row)
+		}
+
+		return kept, deleted, nil
+	})
+}
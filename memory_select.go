@@ -0,0 +1,404 @@
+package somesql
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// MemorySelect is a Select run against a Memory store
+// Implements: Accessor
+type MemorySelect struct {
+	*Select
+	memory *Memory
+}
+
+// Rows implements Accessor
+func (s MemorySelect) Rows() (*sql.Rows, error) {
+	return s.RowsContext(context.Background())
+}
+
+// RowsContext implements Accessor
+func (s MemorySelect) RowsContext(ctx context.Context) (*sql.Rows, error) {
+	result, err := s.memory.query(*s.Select)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.memory.rows.query(ctx, result)
+}
+
+// One returns the first Document matching Select
+// ErrNotFound is returned if there is none
+func (s MemorySelect) One() (Document, error) {
+	return s.OneContext(context.Background())
+}
+
+// OneContext returns the first Document matching Select
+// ErrNotFound is returned if there is none
+func (s MemorySelect) OneContext(ctx context.Context) (Document, error) {
+	var (
+		doc   Document
+		found bool
+	)
+
+	one := *s.Select
+	one.limit = 1
+
+	err := MemorySelect{Select: &one, memory: s.memory}.EachContext(ctx, func(d Document) error {
+		doc, found = d, true
+		return nil
+	})
+	if err != nil {
+		return doc, err
+	}
+
+	if !found {
+		return doc, ErrNotFound
+	}
+
+	return doc, nil
+}
+
+// All returns all Documents matching Select
+func (s MemorySelect) All() ([]Document, error) {
+	return s.AllContext(context.Background())
+}
+
+// AllContext returns all Documents matching Select
+func (s MemorySelect) AllContext(ctx context.Context) ([]Document, error) {
+	docs := make([]Document, 0)
+
+	err := s.EachContext(ctx, func(d Document) error {
+		docs = append(docs, d)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return docs, nil
+}
+
+// Each calls fn for every Document matching Select
+// Iteration stops at the first error returned by fn
+func (s MemorySelect) Each(fn func(Document) error) error {
+	return s.EachContext(context.Background(), fn)
+}
+
+// EachContext calls fn for every Document matching Select
+// Iteration stops at the first error returned by fn
+func (s MemorySelect) EachContext(ctx context.Context, fn func(Document) error) error {
+	rows, err := s.RowsContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	return scanDocuments(rows, s.GetLang(), fn)
+}
+
+// OneInto decodes the first Document matching Select into struct pointed by dst
+// ErrNotFound is returned if there is none
+func (s MemorySelect) OneInto(dst interface{}) error {
+	return s.OneIntoContext(context.Background(), dst)
+}
+
+// OneIntoContext decodes the first Document matching Select into struct pointed by dst
+// ErrNotFound is returned if there is none
+func (s MemorySelect) OneIntoContext(ctx context.Context, dst interface{}) error {
+	doc, err := s.OneContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	return doc.Decode(dst)
+}
+
+// AllInto decodes all Documents matching Select into slice of structs pointed by dst
+func (s MemorySelect) AllInto(dst interface{}) error {
+	return s.AllIntoContext(context.Background(), dst)
+}
+
+// AllIntoContext decodes all Documents matching Select into slice of structs pointed by dst
+func (s MemorySelect) AllIntoContext(ctx context.Context, dst interface{}) error {
+	return decodeAll(dst, func(fn func(Document) error) error {
+		return s.EachContext(ctx, fn)
+	})
+}
+
+// query returns the projected rows matching s
+func (m *Memory) query(s Select) (*memoryResult, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	rows, err := m.selectRows(s)
+	if err != nil {
+		return nil, err
+	}
+
+	return projectRows(s.GetTable(), s.GetLang(), s.fields, s.IsInner(), rows, s.fallback...), nil
+}
+
+// subSelect returns the Select of the sub-query of a ConditionQuery
+func subSelect(query Accessor) (*Select, error) {
+	switch q := query.(type) {
+	case *Select:
+		return q, nil
+	case *MemorySelect:
+		return q.Select, nil
+	case MemorySelect:
+		return q.Select, nil
+	}
+
+	return nil, fmt.Errorf("%w: sub-query %T", ErrUnsupported, query)
+}
+
+// queryValues returns the values of the single field projected by the sub-query of a ConditionQuery
+func (m *Memory) queryValues(query Accessor) ([]interface{}, error) {
+	s, err := subSelect(query)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(s.fields) != 1 {
+		return nil, fmt.Errorf("%w: sub-query of %d fields", ErrUnsupported, len(s.fields))
+	}
+
+	rows, err := m.selectRows(*s)
+	if err != nil {
+		return nil, err
+	}
+
+	values := make([]interface{}, 0, len(rows))
+	for _, row := range rows {
+		values = append(values, s.memoryValue(row, s.fields[0]))
+	}
+
+	return values, nil
+}
+
+// selectRows returns the rows matching s, ordered, limited and offset
+// errors of the builder are returned before rows are read
+func (m *Memory) selectRows(s Select) ([]memoryRow, error) {
+	if err := s.memorySupported(); err != nil {
+		return nil, err
+	}
+
+	if _, _, err := s.Build(); err != nil {
+		return nil, err
+	}
+
+	rows, err := m.filterRows(s.GetTable(), withContext(s.conditions, s.conditionContext()))
+	if err != nil {
+		return nil, err
+	}
+
+	sortRows(rows, s.order, s.memoryValue)
+
+	if s.offset >= len(rows) {
+		rows = nil
+	} else if s.offset > 0 {
+		rows = rows[s.offset:]
+	}
+
+	if s.limit > 0 && s.limit < len(rows) {
+		rows = rows[:s.limit]
+	}
+
+	return rows, nil
+}
+
+// filterRows returns the rows of table matching conds
+func (m *Memory) filterRows(table Table, conds []Condition) ([]memoryRow, error) {
+	var rows []memoryRow
+
+	for _, row := range m.tables[table.Name] {
+		ok, err := m.matchConditions(row, conds)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			rows = append(rows, row)
+		}
+	}
+
+	return rows, nil
+}
+
+// memorySupported returns ErrUnsupported if Select cannot be evaluated by the memory store
+func (s Select) memorySupported() error {
+	var feature string
+
+	switch {
+	case s.isAggregated() || len(s.having) > 0:
+		feature = "aggregations"
+	case s.keyset:
+		feature = "keyset pagination"
+	case len(s.includes) > 0:
+		feature = "included relations"
+	case len(s.headlines) > 0:
+		feature = "search headlines"
+	case s.langs != nil:
+		feature = "langs"
+	}
+
+	for _, o := range s.order {
+		if o.search != nil {
+			feature = "search rank"
+		}
+	}
+
+	if feature != "" {
+		return fmt.Errorf("%w: %s", ErrUnsupported, feature)
+	}
+
+	return nil
+}
+
+// memoryValue returns the value of field of row as read by Select (see orderExpression)
+// inner fields are read as text
+func (s Select) memoryValue(row memoryRow, field string) interface{} {
+	var (
+		lang  = s.GetLang()
+		table = s.GetTable()
+	)
+
+	if table.IsMeta(field) || table.IsJSONB(field) {
+		return row[GetLangField(field, lang)]
+	} else if parent, innerField, ok := table.GetInnerField(field); ok {
+		return getInnerText(row, parent, lang, s.fallback, innerField)
+	} else if len(table.JSONBFields) > 0 { // defaults to inner field of first JSONB field
+		return getInnerText(row, table.JSONBFields[0], lang, s.fallback, field)
+	}
+
+	return row[field]
+}
+
+// projectRows returns the columns and values of fields of rows (see processFields)
+// inner fields of JSONB fields are grouped as a JSON object, unless within an inner query
+func projectRows(table Table, lang string, fields []string, isInnerQuery bool, rows []memoryRow, fallback ...string) *memoryResult {
+	var (
+		result       = &memoryResult{values: make([][]driver.Value, 0, len(rows))}
+		metaFields   []string
+		innerFields  []string
+		jsonbFields  = make(map[string][]string)
+		jsonbParents []string
+	)
+
+	if len(fields) == 0 {
+		fields = table.Fields()
+	}
+
+	for _, f := range fields {
+		if table.IsMeta(f) || table.IsJSONB(f) {
+			metaFields = append(metaFields, f)
+			result.columns = append(result.columns, GetLangField(f, lang))
+		} else if parent, innerField, ok := table.GetInnerField(f); ok {
+			if IsFieldRelations(parent) { // relations are stored within data
+				parent = FieldData
+			}
+
+			if isInnerQuery {
+				innerFields = append(innerFields, f)
+				continue
+			}
+
+			if _, ok := jsonbFields[parent]; !ok {
+				jsonbParents = append(jsonbParents, parent)
+			}
+			jsonbFields[parent] = append(jsonbFields[parent], innerField)
+		}
+	}
+
+	for _, f := range innerFields {
+		_, innerField, _ := table.GetInnerField(f)
+		result.columns = append(result.columns, innerField)
+	}
+	result.columns = append(result.columns, jsonbParents...)
+
+	hasLang := len(fallback) > 0 && !isInnerQuery
+	if hasLang {
+		result.columns = append(result.columns, FieldDataLang)
+	}
+
+	for _, row := range rows {
+		values := make([]driver.Value, 0, len(result.columns))
+
+		for _, f := range metaFields {
+			values = append(values, driverValue(row.getColumn(f, lang, fallback)))
+		}
+
+		for _, f := range innerFields {
+			parent, innerField, _ := table.GetInnerField(f)
+			values = append(values, driverValue(getInnerText(row, parent, lang, fallback, innerField)))
+		}
+
+		for _, parent := range jsonbParents {
+			var (
+				object  = make(map[string]interface{})
+				isWhole = make(map[string]bool)
+			)
+
+			for _, innerField := range jsonbFields[parent] {
+				if hasWholeParent(innerField, isWhole) {
+					continue
+				}
+				isWhole[innerField] = true
+
+				value, _ := row.getInner(parent, lang, fallback, innerField)
+				setNestedValue(object, innerField, value)
+			}
+
+			values = append(values, driverValue(object))
+		}
+
+		if hasLang {
+			values = append(values, row.getLang(lang, fallback))
+		}
+
+		result.values = append(result.values, values)
+	}
+
+	return result
+}
+
+// hasWholeParent returns true if innerField or any of its parents is within isWhole
+func hasWholeParent(innerField string, isWhole map[string]bool) bool {
+	if isWhole[innerField] {
+		return true
+	}
+
+	for _, parent := range getParentPaths(innerField) {
+		if isWhole[parent] {
+			return true
+		}
+	}
+
+	return false
+}
+
+// driverValue returns value as a database/sql driver value, JSON documents are encoded
+func driverValue(value interface{}) driver.Value {
+	switch v := value.(type) {
+	case nil, string, []byte, bool, int64, float64, time.Time:
+		return v
+	case map[string]interface{}, []interface{}:
+		jsonBytes, err := json.Marshal(v)
+		if err != nil {
+			return nil
+		}
+		return jsonBytes
+	case int, int8, int16, int32, uint, uint8, uint16, uint32, uint64:
+		f, _ := asFloat(v)
+		return int64(f)
+	case float32:
+		return float64(v)
+	}
+
+	text, _ := asText(value)
+
+	return text
+}
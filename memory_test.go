@@ -0,0 +1,355 @@
+package somesql_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.lsl.digital/lardwaz/somesql"
+)
+
+func newMemoryFixture(t *testing.T) *somesql.Memory {
+	m := somesql.NewMemory()
+
+	err := m.Insert(somesql.NewInsert("en").Batch(
+		somesql.NewFields().ID("a1").Type("article").OwnerID("o1").Set("data.title", "Hello world").Set("data.views", 30).Set("data.published", true).Set("data.seo.title", "Hello").Set("relations.tags", []string{"news", "sport"}).Set("relations.author", []string{"u1"}),
+		somesql.NewFields().ID("a2").Type("article").OwnerID("o2").Set("data.title", "Another story").Set("data.views", 5).Set("data.published", false).Set("relations.tags", []string{"culture"}).Set("relations.author", []string{"u2"}),
+		somesql.NewFields().ID("a3").Type("article").OwnerID("o1").Set("data.title", "Breaking news").Set("data.views", 100).Set("data.published", true).Set("relations.tags", []string{"news"}),
+		somesql.NewFields().ID("u1").Type("author").Set("data.name", "Alice"),
+		somesql.NewFields().ID("u2").Type("author").Set("data.name", "Bob").Set("data.active", false),
+	)).Exec(true)
+	assert.Nil(t, err, "fixture insert")
+
+	err = m.Insert(somesql.NewInsert("fr").Fields(somesql.NewFields().ID("a2").Set("data.title", "Une autre histoire")).OnConflict(somesql.ConflictMerge)).Exec(true)
+	assert.Nil(t, err, "fixture insert fr")
+
+	return m
+}
+
+func ids(docs []somesql.Document) []string {
+	ids := make([]string, 0, len(docs))
+	for _, d := range docs {
+		ids = append(ids, d.ID)
+	}
+
+	return ids
+}
+
+func TestMemory_Select(t *testing.T) {
+	type testCase struct {
+		name        string
+		query       *somesql.Select
+		expectedIDs []string
+	}
+
+	tests := []testCase{
+		{
+			name:        "all",
+			query:       somesql.NewSelect("en"),
+			expectedIDs: []string{"a1", "a2", "a3", "u1", "u2"},
+		},
+		{
+			name:        "meta condition",
+			query:       somesql.NewSelect("en").Where(somesql.And("en", "type", "=", "author")),
+			expectedIDs: []string{"u1", "u2"},
+		},
+		{
+			name:        "data condition compared as text",
+			query:       somesql.NewSelect("en").Where(somesql.And("en", "data.title", "=", "Hello world")),
+			expectedIDs: []string{"a1"},
+		},
+		{
+			name:        "boolean condition",
+			query:       somesql.NewSelect("en").Where(somesql.And("en", "data.published", "=", true)),
+			expectedIDs: []string{"a1", "a3"},
+		},
+		{
+			name:        "AND takes precedence over OR",
+			query:       somesql.NewSelect("en").Where(somesql.And("en", "type", "=", "author")).Where(somesql.And("en", "data.name", "=", "Bob")).Where(somesql.Or("en", "id", "=", "a1")),
+			expectedIDs: []string{"a1", "u2"},
+		},
+		{
+			name:        "group",
+			query:       somesql.NewSelect("en").Where(somesql.And("en", "owner_id", "=", "o1")).Where(somesql.AndGroup(somesql.And("en", "data.views", "=", 100), somesql.Or("en", "data.title", "LIKE", "Hello%"))).Order("id", false),
+			expectedIDs: []string{"a3", "a1"},
+		},
+		{
+			name:        "function",
+			query:       somesql.NewSelect("en").Where(somesql.And("en", "data.title", "=", "hello world", "LOWER")),
+			expectedIDs: []string{"a1"},
+		},
+		{
+			name:        "relations membership",
+			query:       somesql.NewSelect("en").Where(somesql.And("en", "relations.tags", "=", "news")),
+			expectedIDs: []string{"a1", "a3"},
+		},
+		{
+			name:        "IN",
+			query:       somesql.NewSelect("en").Where(somesql.AndIn("en", "id", []string{"a2", "u1", "x"})),
+			expectedIDs: []string{"a2", "u1"},
+		},
+		{
+			name:        "IN of interface values",
+			query:       somesql.NewSelect("en").Where(somesql.AndIn("en", "owner_id", []interface{}{"o2", "x"})),
+			expectedIDs: []string{"a2"},
+		},
+		{
+			name:        "NOT IN relations",
+			query:       somesql.NewSelect("en").Where(somesql.And("en", "type", "=", "article")).Where(somesql.AndNotIn("en", "relations.tags", []string{"news", "x"})),
+			expectedIDs: []string{"a2"},
+		},
+		{
+			name:        "IN query",
+			query:       somesql.NewSelect("en").Where(somesql.AndInQuery("en", "id", somesql.NewSelectInner("en").Fields("id").Where(somesql.And("en", "relations.tags", "=", "news")))),
+			expectedIDs: []string{"a1", "a3"},
+		},
+		{
+			name:        "NOT IN query",
+			query:       somesql.NewSelect("en").Where(somesql.AndNotInQuery("en", "owner_id", somesql.NewSelectInner("en").Fields("owner_id").Where(somesql.And("en", "id", "=", "a1")))),
+			expectedIDs: []string{"a2", "u1", "u2"},
+		},
+		{
+			name:        "EXISTS query",
+			query:       somesql.NewSelect("en").Where(somesql.And("en", "type", "=", "author")).Where(somesql.AndExists(somesql.NewSelectInner("en").Fields("id").Where(somesql.And("en", "data.name", "=", "Bob")))),
+			expectedIDs: []string{"u1", "u2"},
+		},
+		{
+			name:        "NOT EXISTS query",
+			query:       somesql.NewSelect("en").Where(somesql.AndNotExists(somesql.NewSelectInner("en").Fields("id").Where(somesql.And("en", "data.name", "=", "Bob")))),
+			expectedIDs: []string{},
+		},
+		{
+			name:        "BETWEEN numbers",
+			query:       somesql.NewSelect("en").Where(somesql.AndBetween("en", "data.views", 10, 100)),
+			expectedIDs: []string{"a1", "a3"},
+		},
+		{
+			name:        "IS NULL",
+			query:       somesql.NewSelect("en").Where(somesql.AndIsNull("en", "data.views")).Where(somesql.OrIsNotNull("en", "data.seo.title")),
+			expectedIDs: []string{"a1", "u1", "u2"},
+		},
+		{
+			name:        "regular expression",
+			query:       somesql.NewSelect("en").Where(somesql.And("en", "data.title", somesql.OpIMatch, "^(hello|breaking) ")),
+			expectedIDs: []string{"a1", "a3"},
+		},
+		{
+			name:        "key existence",
+			query:       somesql.NewSelect("en").Where(somesql.AndHasKey("en", "data", "active")).Where(somesql.OrHasAnyKey("en", "relations.tags", "culture", "x")),
+			expectedIDs: []string{"a2", "u2"},
+		},
+		{
+			name:        "containment",
+			query:       somesql.NewSelect("en").Where(somesql.AndContains("en", "relations.tags", []string{"sport", "news"})).Where(somesql.OrContains("en", "data", map[string]interface{}{"views": 5, "published": false})),
+			expectedIDs: []string{"a1", "a2"},
+		},
+		{
+			name:        "NOT group",
+			query:       somesql.NewSelect("en").Where(somesql.And("en", "type", "=", "article")).Where(somesql.AndNot(somesql.AndContains("en", "relations.tags", []string{"news"}), somesql.And("en", "owner_id", "=", "o1"))),
+			expectedIDs: []string{"a2"},
+		},
+		{
+			name:        "NOT within group",
+			query:       somesql.NewSelect("en").Where(somesql.AndGroup(somesql.And("en", "type", "=", "author"), somesql.OrNot(somesql.And("en", "relations.tags", "=", "news"), somesql.Or("en", "type", "=", "author")))),
+			expectedIDs: []string{"a2", "u1", "u2"},
+		},
+		{
+			name:        "NOT of a missing key is unknown",
+			query:       somesql.NewSelect("en").Where(somesql.AndNot(somesql.And("en", "data.name", "=", "Bob"))),
+			expectedIDs: []string{"u1"},
+		},
+		{
+			name:        "NOT of unknown AND false",
+			query:       somesql.NewSelect("en").Where(somesql.AndNot(somesql.And("en", "data.name", "=", "Bob"), somesql.And("en", "type", "=", "author"))),
+			expectedIDs: []string{"a1", "a2", "a3", "u1"},
+		},
+		{
+			name:        "NOT of unknown OR true",
+			query:       somesql.NewSelect("en").Where(somesql.AndNot(somesql.AndHasKey("en", "data.seo", "title"), somesql.Or("en", "type", "=", "author"))),
+			expectedIDs: []string{},
+		},
+		{
+			name:        "order by data as text, limit and offset",
+			query:       somesql.NewSelect("en").Where(somesql.And("en", "type", "=", "article")).Order("data.views", true).Limit(2).Offset(1),
+			expectedIDs: []string{"a1", "a2"},
+		},
+		{
+			name:        "order with NULLs first when descending",
+			query:       somesql.NewSelect("en").Order("data.views", false).Order("id", true),
+			expectedIDs: []string{"u1", "u2", "a2", "a1", "a3"},
+		},
+		{
+			name:        "fallback",
+			query:       somesql.NewSelect("fr").Fallback("en").Where(somesql.And("fr", "data.title", "LIKE", "%histoire")),
+			expectedIDs: []string{"a2"},
+		},
+		{
+			name:        "fallback of a partly translated document",
+			query:       somesql.NewSelect("fr").Fallback("en").Where(somesql.AndBetween("fr", "data.views", 5, 1000)).Order("id", true),
+			expectedIDs: []string{"a1", "a3"},
+		},
+	}
+
+	m := newMemoryFixture(t)
+
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			docs, err := m.Select(tt.query).All()
+
+			assert.Nil(t, err, fmt.Sprintf("%02d. %s :: error", i+1, tt.name))
+			assert.Equal(t, tt.expectedIDs, ids(docs), fmt.Sprintf("%02d. %s :: invalid documents", i+1, tt.name))
+		})
+	}
+}
+
+func TestMemory_Projection(t *testing.T) {
+	m := newMemoryFixture(t)
+
+	doc, err := m.Select(somesql.NewSelect("en").Fields("id", "data.title", "data.seo.title", "relations.tags").Where(somesql.And("en", "id", "=", "a1"))).One()
+	assert.Nil(t, err)
+	assert.Equal(t, "a1", doc.ID)
+	assert.Equal(t, "", doc.Type)
+	assert.Equal(t, map[string]interface{}{
+		"title": "Hello world",
+		"seo":   map[string]interface{}{"title": "Hello"},
+		"tags":  []interface{}{"news", "sport"},
+	}, doc.Data)
+
+	doc, err = m.Select(somesql.NewSelect("fr").Fallback("en").Where(somesql.And("fr", "id", "=", "a1"))).One()
+	assert.Nil(t, err)
+	assert.Equal(t, "en", doc.Lang)
+	assert.Equal(t, "Hello world", doc.Data["title"])
+
+	doc, err = m.Select(somesql.NewSelect("fr").Fallback("en").Fields("id", "data.title", "data.views").Where(somesql.And("fr", "id", "=", "a2"))).One()
+	assert.Nil(t, err)
+	assert.Equal(t, "fr", doc.Lang, "partly translated documents are read from their lang")
+	assert.Equal(t, map[string]interface{}{"title": "Une autre histoire", "views": nil}, doc.Data, "inner fields are not read from the fallback langs")
+
+	_, err = m.Select(somesql.NewSelect("en").Where(somesql.And("en", "id", "=", "x"))).One()
+	assert.Equal(t, somesql.ErrNotFound, err)
+
+	_, err = m.Select(somesql.NewSelect("en").Count("count")).All()
+	assert.True(t, errors.Is(err, somesql.ErrUnsupported), "aggregations are not supported")
+
+	// correlated sub-queries (Ref) are not evaluated by the memory store, they are covered against SQL in TestConditionQuery_Correlated
+	_, err = m.Select(somesql.NewSelect("en").As("author").Where(somesql.AndExists(somesql.NewSelectInner("en").Where(somesql.And("en", "relations.author", "=", somesql.Ref("author", "id")))))).All()
+	assert.True(t, errors.Is(err, somesql.ErrUnsupported), "references to the outer statement are not supported")
+}
+
+func TestMemory_SubQueryLimit(t *testing.T) {
+	m := somesql.NewMemory()
+
+	rows := make([]somesql.Fields, 0, 12)
+	for i := 0; i < 12; i++ {
+		rows = append(rows, somesql.NewFields().ID(fmt.Sprintf("d%02d", i)).Type("article"))
+	}
+	err := m.Insert(somesql.NewInsert("en").Batch(rows...)).Exec(true)
+	assert.Nil(t, err)
+
+	docs, err := m.Select(somesql.NewSelect("en").Where(somesql.AndInQuery("en", "id", somesql.NewSelectInner("en").Fields("id").Where(somesql.And("en", "type", "=", "article")))).Limit(0)).All()
+	assert.Nil(t, err)
+	assert.Len(t, docs, 12, "sub-queries are not limited")
+}
+
+func TestMemory_Into(t *testing.T) {
+	m := newMemoryFixture(t)
+
+	var a article
+	err := m.Select(somesql.NewSelect("en").Fields(somesql.StructFields(article{})...).Where(somesql.And("en", "id", "=", "a1"))).OneInto(&a)
+	assert.Nil(t, err)
+	assert.Equal(t, "a1", a.ID)
+	assert.Equal(t, "Hello world", a.Title)
+	assert.Equal(t, 30, a.Views)
+	assert.Equal(t, []string{"u1"}, a.Authors)
+
+	err = m.Select(somesql.NewSelect("en").Where(somesql.And("en", "id", "=", "x"))).OneInto(&a)
+	assert.Equal(t, somesql.ErrNotFound, err)
+
+	var articles []article
+	err = m.Select(somesql.NewSelect("en").Where(somesql.And("en", "type", "=", "article")).Order("id", true)).AllInto(&articles)
+	assert.Nil(t, err)
+	assert.Len(t, articles, 3)
+	assert.Equal(t, "Another story", articles[1].Title)
+
+	var pointers []*article
+	err = m.Select(somesql.NewSelect("en").Where(somesql.And("en", "type", "=", "author"))).AllInto(&pointers)
+	assert.Nil(t, err)
+	assert.Len(t, pointers, 2)
+
+	err = m.Select(somesql.NewSelect("en")).AllInto(articles)
+	assert.Error(t, err, "non pointer must fail")
+}
+
+func TestMemory_Mutations(t *testing.T) {
+	m := newMemoryFixture(t)
+
+	err := m.Insert(somesql.NewInsert("en").Fields(somesql.NewFields().ID("a1"))).Exec(true)
+	assert.Equal(t, somesql.ErrDuplicateKey, err, "insert duplicate id")
+
+	err = m.Update(somesql.NewUpdate("en").Fields(somesql.NewFields().Type("page").Set("data.title", "Hi").Set("data.seo.description", "abc").Add("relations.tags", "sport").AddUnique("relations.author", []string{"u1", "u2"}).Remove("data.missing", "x")).Where(somesql.And("en", "id", "=", "a1"))).Exec(true)
+	assert.Nil(t, err, "update")
+
+	doc, err := m.Select(somesql.NewSelect("en").Where(somesql.And("en", "id", "=", "a1"))).One()
+	assert.Nil(t, err)
+	assert.Equal(t, "page", doc.Type)
+	assert.Equal(t, map[string]interface{}{
+		"title":     "Hi",
+		"views":     float64(30),
+		"published": true,
+		"seo":       map[string]interface{}{"title": "Hello", "description": "abc"},
+		"tags":      []interface{}{"news", "sport", "sport"},
+		"author":    []interface{}{"u1", "u2"},
+		"missing":   []interface{}{},
+	}, doc.Data)
+
+	docs, err := m.Update(somesql.NewUpdate("en").Fields(somesql.NewFields().Set("data.name", "Carol").Set("data.seo.title", "C")).Replace(true).Where(somesql.And("en", "type", "=", "author"))).ExecReturning(true)
+	assert.Nil(t, err, "update replace")
+	assert.Equal(t, []string{"u1", "u2"}, ids(docs))
+	assert.Equal(t, map[string]interface{}{"name": "Carol", "seo": map[string]interface{}{"title": "C"}}, docs[1].Data)
+
+	err = m.Update(somesql.NewUpdate("en").Fields(somesql.NewFields().Remove("relations.tags", []string{"news", "x"})).Where(somesql.And("en", "relations.tags", "=", "news"))).Exec(true)
+	assert.Nil(t, err, "update remove")
+
+	docs, err = m.Select(somesql.NewSelect("en").Where(somesql.And("en", "relations.tags", "=", "sport"))).All()
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"a1"}, ids(docs))
+	assert.Equal(t, []interface{}{"sport", "sport"}, docs[0].Data["tags"])
+
+	docs, err = m.Delete(somesql.NewDelete("en").Where(somesql.And("en", "type", "=", "article"))).ExecReturning(true)
+	assert.Nil(t, err, "delete")
+	assert.Equal(t, []string{"a2", "a3"}, ids(docs))
+	assert.Equal(t, 3, m.Len(somesql.TableRepo))
+}
+
+func TestMemory_UpdateStoredValue(t *testing.T) {
+	m := newMemoryFixture(t)
+
+	// Postgres evaluates each path of SET against the stored column: seo is read back before keywords are appended
+	docs, err := m.Update(somesql.NewUpdate("en").Fields(somesql.NewFields().Set("data.seo", "x").Add("data.seo.keywords", "k").Add("relations.tags", "culture")).Where(somesql.And("en", "id", "=", "a1"))).ExecReturning(true)
+	assert.Nil(t, err)
+	if assert.Len(t, docs, 1) {
+		assert.Equal(t, map[string]interface{}{"title": "Hello", "keywords": []interface{}{"k"}}, docs[0].Data["seo"])
+		assert.Equal(t, []interface{}{"news", "sport", "culture"}, docs[0].Data["tags"])
+	}
+}
+
+func TestMemory_Transactions(t *testing.T) {
+	m := newMemoryFixture(t)
+	update := m.Update(somesql.NewUpdate("en").Fields(somesql.NewFields().Type("page")).Where(somesql.And("en", "id", "=", "a1")))
+
+	assert.True(t, errors.Is(update.ExecTx(nil, true), somesql.ErrUnsupported), "ExecTx")
+	_, err := update.ExecTxReturning(nil, true)
+	assert.True(t, errors.Is(err, somesql.ErrUnsupported), "ExecTxReturning")
+	_, err = update.RowsTx(nil)
+	assert.True(t, errors.Is(err, somesql.ErrUnsupported), "RowsTx")
+	assert.True(t, errors.Is(update.Exec(false), somesql.ErrUnsupported), "Exec without autocommit")
+	_, err = m.Delete(somesql.NewDelete("en")).ExecReturning(false)
+	assert.True(t, errors.Is(err, somesql.ErrUnsupported), "ExecReturning without autocommit")
+	assert.True(t, errors.Is(m.Insert(somesql.NewInsert("en").Fields(somesql.NewFields().ID("x"))).ExecTx(nil, true), somesql.ErrUnsupported), "insert ExecTx")
+
+	doc, err := m.Select(somesql.NewSelect("en").Where(somesql.And("en", "id", "=", "a1"))).One()
+	assert.Nil(t, err)
+	assert.Equal(t, "article", doc.Type, "statements within transactions are not applied")
+	assert.Equal(t, 5, m.Len(somesql.TableRepo))
+}
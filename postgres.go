@@ -51,3 +51,131 @@ func processConditions(conds []Condition) (string, []interface{}) {
 
 	return conditionsBuff.String(), values
 }
+
+// processFields returns the projection of fields from table
+// inner fields of JSONB fields are grouped as a JSON object, unless within an inner query
+// data fields are read across the fallback langs if any
+func processFields(d Dialect, table Table, lang string, fields []string, isInnerQuery bool, fallback ...string) string {
+	var (
+		fieldsStr string
+
+		fieldsBuff     strings.Builder
+		metaFieldsBuff strings.Builder
+		innerBuff      strings.Builder
+		jsonbObjects   = make(map[string]*jsonbObject)
+		jsonbParents   []string
+	)
+
+	if len(fields) == 0 {
+		fields = table.Fields()
+	}
+
+	// Processing fields
+	for _, f := range fields {
+		if table.IsMeta(f) || table.IsJSONB(f) {
+			column := getFallbackColumn(d, f, lang, fallback)
+			if column != quoteIdentifier(GetLangField(f, lang)) {
+				column += ` ` + quoteIdentifier(GetLangField(f, lang))
+			}
+			metaFieldsBuff.WriteString(column + `, `)
+		} else if parent, innerField, ok := table.GetInnerField(f); ok {
+			accessor := getFallbackAccessor(d, parent, lang, fallback, innerField, isInnerQuery)
+			if IsFieldRelations(parent) { // relations are stored within data
+				parent = FieldData
+			}
+
+			if isInnerQuery {
+				innerBuff.WriteString(accessor + ` ` + quoteIdentifier(innerField) + `, `)
+				continue
+			}
+
+			object, ok := jsonbObjects[parent]
+			if !ok {
+				object = &jsonbObject{}
+				jsonbObjects[parent] = object
+				jsonbParents = append(jsonbParents, parent)
+			}
+			object.add(innerField, accessor)
+		}
+	}
+
+	// Put everything back in order
+	// Meta fields
+	if metaFieldsBuff.Len() > 0 {
+		metaFieldsStr := metaFieldsBuff.String()[:metaFieldsBuff.Len()-2] // trim ", "
+		fieldsBuff.WriteString(metaFieldsStr + `, `)
+	}
+
+	// JSONB fields
+	if innerBuff.Len() > 0 {
+		fieldsBuff.WriteString(innerBuff.String())
+	}
+	for _, parent := range jsonbParents {
+		fieldsBuff.WriteString(jsonbObjects[parent].SQL(d) + ` ` + quoteIdentifier(parent) + `, `)
+	}
+
+	if fieldsBuff.Len() > 0 {
+		fieldsStr = fieldsBuff.String()[:fieldsBuff.Len()-2] // trim ", "
+	}
+
+	return fieldsStr
+}
+
+// jsonbObject represents a JSON object built from (nested) inner fields
+type jsonbObject struct {
+	keys     []string
+	values   map[string]string
+	children map[string]*jsonbObject
+}
+
+// add sets the SQL value of dot-separated innerField
+func (o *jsonbObject) add(innerField, value string) {
+	if o.values == nil {
+		o.values = make(map[string]string)
+		o.children = make(map[string]*jsonbObject)
+	}
+
+	parts := strings.SplitN(innerField, ".", 2)
+	key := parts[0]
+
+	_, isValue := o.values[key]
+	child, isChild := o.children[key]
+	if !isValue && !isChild {
+		o.keys = append(o.keys, key)
+	}
+
+	if len(parts) == 1 { // the whole value includes any nested field
+		o.values[key] = value
+		delete(o.children, key)
+	} else if !isValue {
+		if !isChild {
+			child = &jsonbObject{}
+			o.children[key] = child
+		}
+		child.add(parts[1], value)
+	}
+}
+
+// SQL returns the SQL building the object in dialect d
+func (o jsonbObject) SQL(d Dialect) string {
+	var pairs []string
+
+	for _, key := range o.keys {
+		if value, ok := o.values[key]; ok {
+			pairs = append(pairs, quoteLiteral(key), value)
+		} else {
+			pairs = append(pairs, quoteLiteral(key), o.children[key].SQL(d))
+		}
+	}
+
+	return d.JSONObject(pairs)
+}
+
+// processReturning returns the RETURNING clause of a mutator
+func processReturning(d Dialect, table Table, lang string, fields []string) string {
+	if len(fields) == 0 {
+		return ""
+	}
+
+	return "RETURNING " + processFields(d, table, lang, fields, false)
+}
@@ -1,6 +1,7 @@
 package somesql
 
 import (
+	"context"
 	"database/sql"
 	"errors"
 
@@ -8,11 +9,15 @@ import (
 )
 
 func rows(sql string, values []interface{}, db *sql.DB) (*sql.Rows, error) {
-	if sql == "" || len(values) == 0 {
-		return nil, errors.New("invalid sql or values")
+	return rowsContext(context.Background(), sql, values, db)
+}
+
+func rowsContext(ctx context.Context, sql string, values []interface{}, db *sql.DB) (*sql.Rows, error) {
+	if sql == "" {
+		return nil, errors.New("invalid sql")
 	}
 
-	rows, err := db.Query(sql, values...)
+	rows, err := db.QueryContext(ctx, sql, values...)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,193 @@
+package somesql
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrInvalidCursor is returned when a cursor does not match the order of Select, or Select has an Offset
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// After enables keyset pagination on the Order fields, with id as a tie-breaker
+// Rows following the row of cursor are returned, an empty cursor returns the first page
+// NULLs (i.e documents without an inner field) come first whatever the direction
+func (s *Select) After(cursor string) *Select {
+	s.keyset = true
+	s.cursor = nil
+
+	if cursor == "" {
+		return s
+	}
+
+	values, err := decodeCursor(cursor)
+	if err != nil {
+		s.errs = append(s.errs, err)
+		return s
+	}
+
+	s.cursor = values
+	return s
+}
+
+// CursorFor returns the cursor of doc, usually the last Document of a page
+// Rows following doc are returned by passing the cursor to After
+func (s Select) CursorFor(doc Document) (string, error) {
+	var values []interface{}
+
+	for _, o := range s.keysetOrder() {
+		values = append(values, s.cursorValue(doc, o.field))
+	}
+
+	jsonBytes, err := json.Marshal(values)
+	if err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(jsonBytes), nil
+}
+
+// keysetOrder returns the Order fields followed by id, unless already ordered by id
+// id follows the direction of the last Order field
+func (s Select) keysetOrder() []order {
+	var (
+		orders = s.order
+		asc    = true
+	)
+
+	for _, o := range orders {
+		if o.field == FieldID {
+			return orders
+		}
+		asc = o.order
+	}
+
+	if !s.GetTable().IsMeta(FieldID) {
+		return orders
+	}
+
+	return append(orders[:len(orders):len(orders)], order{field: FieldID, order: asc})
+}
+
+// checkKeyset returns ErrInvalidCursor if Select has an Offset, rows being skipped by the cursor
+func (s Select) checkKeyset() error {
+	if s.keyset && s.offset > 0 {
+		return fmt.Errorf("%w: OFFSET of keyset pagination", ErrInvalidCursor)
+	}
+
+	return nil
+}
+
+// keysetCondition returns the condition selecting rows following the cursor
+// a row comparison is used when all fields are ordered in the same direction and the cursor has no NULL
+// NULLs coming first, they precede any value and are only followed by values
+func (s Select) keysetCondition() (string, []interface{}, error) {
+	var (
+		orders = s.keysetOrder()
+		values []interface{}
+		isSame = true
+
+		fieldsBuff       strings.Builder
+		placeholdersBuff strings.Builder
+		branchesBuff     strings.Builder
+	)
+
+	if len(orders) != len(s.cursor) {
+		return "", nil, ErrInvalidCursor
+	}
+
+	for i, o := range orders {
+		isSame = isSame && o.order == orders[0].order && s.cursor[i] != nil
+		fieldsBuff.WriteString(s.orderExpression(o.field) + `, `)
+		placeholdersBuff.WriteString(`?, `)
+	}
+
+	if isSame {
+		operator := "<"
+		if orders[0].order {
+			operator = ">"
+		}
+
+		fields := fieldsBuff.String()[:fieldsBuff.Len()-2]                   // trim ", "
+		placeholders := placeholdersBuff.String()[:placeholdersBuff.Len()-2] // trim ", "
+
+		return "(" + fields + ") " + operator + " (" + placeholders + ")", s.cursor, nil
+	}
+
+	// (a > ? OR (a = ? AND b < ?) OR ...), a IS NULL / a IS NOT NULL for NULLs of the cursor
+	for i, o := range orders {
+		var branchBuff strings.Builder
+
+		for j := 0; j < i; j++ {
+			if s.cursor[j] == nil {
+				branchBuff.WriteString(s.orderExpression(orders[j].field) + ` IS NULL AND `)
+				continue
+			}
+			branchBuff.WriteString(s.orderExpression(orders[j].field) + ` = ? AND `)
+			values = append(values, s.cursor[j])
+		}
+
+		if s.cursor[i] == nil {
+			branchBuff.WriteString(s.orderExpression(o.field) + ` IS NOT NULL`)
+		} else {
+			operator := "<"
+			if o.order {
+				operator = ">"
+			}
+			branchBuff.WriteString(s.orderExpression(o.field) + ` ` + operator + ` ?`)
+			values = append(values, s.cursor[i])
+		}
+
+		if i == 0 {
+			branchesBuff.WriteString(branchBuff.String())
+		} else {
+			branchesBuff.WriteString(` OR (` + branchBuff.String() + `)`)
+		}
+	}
+
+	return "(" + branchesBuff.String() + ")", values, nil
+}
+
+// cursorValue returns the value of doc for order field
+// inner fields are compared as text
+func (s Select) cursorValue(doc Document, field string) interface{} {
+	table := s.GetTable()
+
+	if !table.IsMeta(field) && !strings.Contains(field, ".") && len(table.JSONBFields) > 0 {
+		field = table.JSONBFields[0] + "." + field // defaults to inner field of first JSONB field
+	}
+
+	value, ok := doc.value(field)
+	if !ok || value == nil {
+		return nil
+	}
+
+	if _, ok := value.(string); ok || !strings.Contains(field, ".") {
+		return value
+	}
+
+	jsonBytes, err := json.Marshal(value)
+	if err != nil {
+		return nil
+	}
+
+	return string(jsonBytes)
+}
+
+// decodeCursor returns the values of cursor
+func decodeCursor(cursor string) ([]interface{}, error) {
+	var values []interface{}
+
+	jsonBytes, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	if err := json.Unmarshal(jsonBytes, &values); err != nil || len(values) == 0 {
+		return nil, ErrInvalidCursor
+	}
+
+	return values, nil
+}
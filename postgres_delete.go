@@ -1,12 +1,13 @@
 package somesql
 
 import (
+	"context"
 	"database/sql"
 	"strconv"
 )
 
-// Delete generates Postgres DELETE statement
-// Implements: Mutator
+// Delete generates DELETE statement of its Dialect (Postgres by default)
+// Implements: Mutator, Returner
 type Delete struct {
 	conditions []Condition
 	offset     int
@@ -15,6 +16,11 @@ type Delete struct {
 	values     []interface{}
 	db         *sql.DB
 	lang       string
+	table      Table
+	dialect    Dialect
+	returning  []string
+	alias      string
+	err        error
 }
 
 // NewDelete returns a new Delete
@@ -22,6 +28,8 @@ func NewDelete(lang string, db ...*sql.DB) *Delete {
 	var s Delete
 
 	s.lang = lang
+	s.table = TableRepo
+	s.dialect = DefaultDialect
 
 	if len(db) > 0 {
 		s.db = db[0]
@@ -50,6 +58,30 @@ func (s Delete) GetLang() string {
 	return s.lang
 }
 
+// SetTable implements Statement
+func (s *Delete) SetTable(t Table) {
+	s.table = t
+}
+
+// GetTable implements Statement
+func (s Delete) GetTable() Table {
+	return s.table
+}
+
+// SetDialect implements Statement
+func (s *Delete) SetDialect(d Dialect) {
+	s.dialect = d
+}
+
+// GetDialect implements Statement
+func (s Delete) GetDialect() Dialect {
+	if s.dialect == nil {
+		return DefaultDialect
+	}
+
+	return s.dialect
+}
+
 // GetSQL implements Statement
 func (s Delete) GetSQL() string {
 	return s.sql
@@ -66,9 +98,17 @@ func (s *Delete) ToSQL() {
 		conditionsStr string
 		offsetStr     string
 		limitStr      string
+		d             = s.GetDialect()
 	)
 
-	conditions, values := processConditions(s.conditions)
+	errs := append(checkLangs(s.GetTable(), s.GetLang()), checkConditions(s.conditions, s.GetTable())...)
+	for _, f := range s.returning {
+		errs = appendError(errs, checkField(s.GetTable(), f))
+	}
+	if s.alias != "" {
+		errs = appendError(errs, checkIdentifier(s.alias, s.alias))
+	}
+	conditions, values := processConditions(withContext(s.conditions, conditionContext{dialect: d}))
 	s.values = values
 
 	if len(conditions) > 0 {
@@ -83,9 +123,23 @@ func (s *Delete) ToSQL() {
 		offsetStr = "OFFSET " + strconv.Itoa(s.offset)
 	}
 
-	sql := "DELETE FROM " + Table + " " + conditionsStr + " " + limitStr + " " + offsetStr
+	sql := "DELETE FROM " + getTableClause(s.table, s.alias) + " " + conditionsStr + " " + limitStr + " " + offsetStr + " " + processReturning(d, s.GetTable(), s.GetLang(), s.returning)
 
-	s.sql = cleanStatement(processPlaceholders(sql))
+	if len(errs) == 0 {
+		if err := checkPlaceholders(sql, s.values); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	s.sql = cleanStatement(d.Placeholders(sql))
+	s.err = newBuildError(errs)
+}
+
+// Build implements Statement
+func (s *Delete) Build() (string, []interface{}, error) {
+	s.ToSQL()
+
+	return s.GetSQL(), s.GetValues(), s.err
 }
 
 // Exec implements Mutator
@@ -94,24 +148,70 @@ func (s Delete) Exec(autocommit bool) error {
 		s.ToSQL()
 	}
 
+	if s.err != nil {
+		return s.err
+	}
+
 	return exec(s.GetSQL(), s.GetValues(), s.GetDB(), autocommit)
 }
 
+// ExecContext implements Mutator
+func (s Delete) ExecContext(ctx context.Context, autocommit bool) error {
+	if s.GetSQL() == "" || len(s.GetValues()) == 0 {
+		s.ToSQL()
+	}
+
+	if s.err != nil {
+		return s.err
+	}
+
+	return execContext(ctx, s.GetSQL(), s.GetValues(), s.GetDB(), autocommit)
+}
+
 // ExecTx implements Mutator
 func (s Delete) ExecTx(tx *sql.Tx, autocommit bool) error {
 	if s.GetSQL() == "" || len(s.GetValues()) == 0 {
 		s.ToSQL()
 	}
 
+	if s.err != nil {
+		return s.err
+	}
+
 	return execTx(s.GetSQL(), s.GetValues(), tx, autocommit)
 }
 
+// ExecTxContext implements Mutator
+func (s Delete) ExecTxContext(ctx context.Context, tx *sql.Tx, autocommit bool) error {
+	if s.GetSQL() == "" || len(s.GetValues()) == 0 {
+		s.ToSQL()
+	}
+
+	if s.err != nil {
+		return s.err
+	}
+
+	return execTxContext(ctx, s.GetSQL(), s.GetValues(), tx, autocommit)
+}
+
+// From sets the table for Delete
+func (s *Delete) From(t Table) *Delete {
+	s.SetTable(t)
+	return s
+}
+
 // Where adds a condition clause to the Query
 func (s *Delete) Where(c Condition) *Delete {
 	s.conditions = append(s.conditions, c)
 	return s
 }
 
+// As names the table of Delete alias, for sub-queries to refer to its fields with Ref
+func (s *Delete) As(alias string) *Delete {
+	s.alias = alias
+	return s
+}
+
 // Offset sets the Offset for Delete
 func (s *Delete) Offset(offset int) *Delete {
 	s.offset = offset
@@ -123,3 +223,80 @@ func (s *Delete) Limit(limit int) *Delete {
 	s.limit = limit
 	return s
 }
+
+// ExecReturning implements Returner
+// Fields of the table are returned unless set with Returning
+func (s Delete) ExecReturning(autocommit bool) ([]Document, error) {
+	return s.ExecReturningContext(context.Background(), autocommit)
+}
+
+// ExecReturningContext implements Returner
+// Fields of the table are returned unless set with Returning
+func (s Delete) ExecReturningContext(ctx context.Context, autocommit bool) ([]Document, error) {
+	s.useDefaultReturning()
+
+	stmts, err := s.statements()
+	if err != nil {
+		return nil, err
+	}
+
+	return execReturning(ctx, stmts, s.GetDB(), autocommit, s.GetLang())
+}
+
+// ExecTxReturning implements Returner
+// Fields of the table are returned unless set with Returning
+func (s Delete) ExecTxReturning(tx *sql.Tx, autocommit bool) ([]Document, error) {
+	return s.ExecTxReturningContext(context.Background(), tx, autocommit)
+}
+
+// ExecTxReturningContext implements Returner
+// Fields of the table are returned unless set with Returning
+func (s Delete) ExecTxReturningContext(ctx context.Context, tx *sql.Tx, autocommit bool) ([]Document, error) {
+	s.useDefaultReturning()
+
+	stmts, err := s.statements()
+	if err != nil {
+		return nil, err
+	}
+
+	return execReturningTx(ctx, stmts, tx, autocommit, s.GetLang())
+}
+
+// RowsTx implements Returner
+// Fields of the table are returned unless set with Returning
+func (s Delete) RowsTx(tx *sql.Tx) (*sql.Rows, error) {
+	return s.RowsTxContext(context.Background(), tx)
+}
+
+// RowsTxContext implements Returner
+// Fields of the table are returned unless set with Returning
+func (s Delete) RowsTxContext(ctx context.Context, tx *sql.Tx) (*sql.Rows, error) {
+	s.useDefaultReturning()
+	s.ToSQL()
+
+	if s.err != nil {
+		return nil, s.err
+	}
+
+	return rowsTx(ctx, s.GetSQL(), s.GetValues(), tx)
+}
+
+// Returning sets the fields returned by Delete
+func (s *Delete) Returning(fields ...string) *Delete {
+	s.returning = fields
+	return s
+}
+
+// useDefaultReturning returns all fields of the table if none were set
+func (s *Delete) useDefaultReturning() {
+	if len(s.returning) == 0 {
+		s.returning = s.GetTable().Fields()
+	}
+}
+
+// statements returns the DELETE statement
+func (s Delete) statements() ([]statement, error) {
+	s.ToSQL()
+
+	return []statement{{sql: s.GetSQL(), values: s.GetValues()}}, s.err
+}
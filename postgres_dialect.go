@@ -0,0 +1,203 @@
+package somesql
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/lib/pq"
+)
+
+// Dialects
+var (
+	// Postgres renders PostgreSQL (JSONB)
+	Postgres Dialect = postgresDialect{}
+
+	// DefaultDialect is the dialect of new statements
+	DefaultDialect = Postgres
+)
+
+// postgresDialect implements Dialect for PostgreSQL
+type postgresDialect struct{}
+
+// Placeholders implements Dialect
+func (postgresDialect) Placeholders(sql string) string {
+	return processPlaceholders(sql)
+}
+
+// Placeholder implements Dialect
+func (postgresDialect) Placeholder(i int) string {
+	return `$` + strconv.Itoa(i)
+}
+
+// ArrayParam implements Dialect
+// values are bound as a single array parameter
+func (postgresDialect) ArrayParam(values []interface{}) (string, []interface{}) {
+	return `?`, []interface{}{pq.Array(values)}
+}
+
+// In implements Dialect
+// the SQL is the same whatever the number of values
+func (postgresDialect) In(lhs, list string, not bool) string {
+	if not {
+		return lhs + ` <> ALL(` + list + `)`
+	}
+
+	return lhs + ` = ANY(` + list + `)`
+}
+
+// Default implements Dialect
+func (postgresDialect) Default() string {
+	return `DEFAULT`
+}
+
+// Cast implements Dialect
+func (postgresDialect) Cast(expr, sqlType string) string {
+	return expr + `::` + sqlType
+}
+
+// Compare implements Dialect
+func (postgresDialect) Compare(lhs, operator, rhs string) string {
+	return lhs + ` ` + operator + ` ` + rhs
+}
+
+// JSONAccessor implements Dialect
+// -> or #> when nested, ->> or #>> as text
+func (postgresDialect) JSONAccessor(expr, innerField string, asText bool) string {
+	operator := "->"
+	if !strings.Contains(innerField, ".") {
+		if asText {
+			operator = "->>"
+		}
+		return expr + operator + quoteLiteral(innerField)
+	}
+
+	operator = "#>"
+	if asText {
+		operator = "#>>"
+	}
+
+	return expr + operator + getJSONBPath(innerField)
+}
+
+// JSONLiteral implements Dialect
+func (postgresDialect) JSONLiteral(json string) string {
+	return `'` + json + `'::JSONB`
+}
+
+// JSONObject implements Dialect
+func (postgresDialect) JSONObject(pairs []string) string {
+	return `json_build_object(` + strings.Join(pairs, `, `) + `)`
+}
+
+// JSONDocument implements Dialect
+func (postgresDialect) JSONDocument(pairs []string) string {
+	return `jsonb_build_object(` + strings.Join(pairs, `, `) + `)`
+}
+
+// JSONParam implements Dialect
+func (postgresDialect) JSONParam() string {
+	return `?::JSONB`
+}
+
+// TypedParam implements Dialect
+func (postgresDialect) TypedParam(sqlType string) string {
+	return `?::` + sqlType
+}
+
+// JSONValue implements Dialect
+func (postgresDialect) JSONValue(sqlType string) string {
+	return `to_jsonb(?::` + sqlType + `)`
+}
+
+// JSONMerge implements Dialect
+func (postgresDialect) JSONMerge(expr, patch string) string {
+	return expr + ` || ` + patch
+}
+
+// JSONSet implements Dialect
+func (postgresDialect) JSONSet(expr, innerField, value string) string {
+	return `jsonb_set(` + expr + `, ` + getJSONBPath(innerField) + `, ` + value + `)`
+}
+
+// JSONArrayAction implements Dialect
+// Arrays are modified in place, without reading them first
+func (postgresDialect) JSONArrayAction(expr string, action uint8) string {
+	current := `COALESCE(` + expr + `, '[]'::JSONB)`
+
+	switch action {
+	case JSONBArrAddUnique:
+		return current + ` || COALESCE((SELECT jsonb_agg(DISTINCT "elem") FROM jsonb_array_elements(?::JSONB) "elem" WHERE NOT ` + current + ` @> jsonb_build_array("elem")), '[]'::JSONB)`
+	case JSONBArrRemove:
+		return `COALESCE((SELECT jsonb_agg("elem") FROM jsonb_array_elements(` + current + `) "elem" WHERE NOT ?::JSONB @> jsonb_build_array("elem")), '[]'::JSONB)`
+	}
+
+	return current + ` || ?::JSONB`
+}
+
+// JSONArrayHas implements Dialect
+// '£' is replaced by '?' (jsonpath filter) once placeholders are processed
+func (postgresDialect) JSONArrayHas(expr, innerField, value string) string {
+	return `jsonb_path_exists(` + expr + `, ` + quoteLiteral(`$.`+innerField+`[*] £ (@  == $val)`) + `, json_object(ARRAY['val', ` + value + `])::jsonb)`
+}
+
+// JSONArrayHasAny implements Dialect
+// innerField is ignored unless it is an array
+func (d postgresDialect) JSONArrayHasAny(expr, innerField, list string) string {
+	array := d.JSONAccessor(expr, innerField, false)
+
+	return `EXISTS (SELECT 1 FROM jsonb_array_elements_text(CASE jsonb_typeof(` + array + `) WHEN 'array' THEN ` + array + ` END) "elem" WHERE "elem" = ANY(` + list + `))`
+}
+
+// JSONHasKeys implements Dialect
+// '£' is replaced by '?' (key existence) once placeholders are processed
+func (postgresDialect) JSONHasKeys(expr, operator string, n int) string {
+	if operator == OpHasKey {
+		return expr + ` £ ?`
+	}
+
+	placeholders := strings.TrimSuffix(strings.Repeat(`?, `, n), `, `)
+
+	return expr + ` £` + strings.TrimPrefix(operator, OpHasKey) + ` ARRAY[` + placeholders + `]::TEXT[]`
+}
+
+// JSONContains implements Dialect
+func (postgresDialect) JSONContains(expr string) string {
+	return expr + ` @> ?::JSONB`
+}
+
+// JSONArrayElements implements Dialect
+func (postgresDialect) JSONArrayElements(expr, alias string) (string, string) {
+	return `CROSS JOIN LATERAL jsonb_array_elements_text(` + expr + `) ` + quoteIdentifier(alias), quoteIdentifier(alias)
+}
+
+// Search implements Dialect
+func (postgresDialect) Search(lang, text string) string {
+	return `to_tsvector('` + GetSearchConfig(lang) + `', ` + text + `) @@ ` + getSearchQuery(lang)
+}
+
+// SearchRank implements Dialect
+func (postgresDialect) SearchRank(lang, text string) string {
+	return `ts_rank(to_tsvector('` + GetSearchConfig(lang) + `', ` + text + `), ` + getSearchQuery(lang) + `)`
+}
+
+// SearchHeadline implements Dialect
+func (postgresDialect) SearchHeadline(lang, text string) string {
+	return `ts_headline('` + GetSearchConfig(lang) + `', ` + text + `, ` + getSearchQuery(lang) + `)`
+}
+
+// Include implements Dialect
+// related rows are joined laterally and aggregated in the order of the array
+func (postgresDialect) Include(table, expr, object, name string) (string, string) {
+	alias := quoteIdentifier(`include_` + name)
+
+	join := `LEFT JOIN LATERAL (SELECT json_agg(` + object + ` ORDER BY "elem"."position") "documents" ` +
+		`FROM jsonb_array_elements_text(` + expr + `) WITH ORDINALITY "elem"("id", "position") ` +
+		`INNER JOIN ` + table + ` "rel" ON "rel"."id" = "elem"."id"::UUID) ` + alias + ` ON TRUE`
+
+	return `COALESCE(` + alias + `."documents", '[]'::JSON)`, join
+}
+
+// getSearchQuery returns the tsquery of a web search placeholder
+func getSearchQuery(lang string) string {
+	return `websearch_to_tsquery('` + GetSearchConfig(lang) + `', ?)`
+}
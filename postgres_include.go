@@ -0,0 +1,85 @@
+package somesql
+
+import (
+	"strings"
+)
+
+// include represents related documents embedded within the results of Select
+type include struct {
+	field      string
+	innerField string
+	fields     []string
+}
+
+// Include embeds the documents referenced by relations field (i.e relations.author) within the results
+// Related documents are read from the same table and lang, projecting fields (all fields of the table by default)
+func (s *Select) Include(field string, fields ...string) *Select {
+	innerField, ok := GetInnerField(FieldRelations, field)
+	if !ok {
+		s.errs = append(s.errs, FieldError{Field: field, Err: ErrUnknownField})
+		return s
+	}
+
+	s.includes = append(s.includes, include{
+		field:      field,
+		innerField: innerField,
+		fields:     fields,
+	})
+	return s
+}
+
+// processIncludes returns the projection and joins of included documents
+// related rows are aggregated in the order of the relations array, read across the fallback chain as the row itself
+func (s Select) processIncludes() (string, string) {
+	var (
+		fieldsBuff  strings.Builder
+		lateralBuff strings.Builder
+		table       = s.GetTable()
+		d           = s.GetDialect()
+		qualifier   = table.Name
+	)
+
+	if s.alias != "" {
+		qualifier = quoteIdentifier(s.alias)
+	}
+
+	for _, inc := range s.includes {
+		expr := d.JSONAccessor(getQualifiedFallbackColumn(d, qualifier, FieldRelations, s.GetLang(), s.fallback), inc.innerField, false)
+		documents, join := d.Include(table.Name, expr, s.includeObject(inc.fields), inc.innerField)
+		if join != "" {
+			lateralBuff.WriteString(join + ` `)
+		}
+
+		fieldsBuff.WriteString(`, ` + documents + ` ` + quoteIdentifier(inc.field))
+	}
+
+	return fieldsBuff.String(), lateralBuff.String()
+}
+
+// includeObject returns the JSON object of a related row with fields, data being read across the fallback chain
+func (s Select) includeObject(fields []string) string {
+	var (
+		object jsonbObject
+		table  = s.GetTable()
+		lang   = s.GetLang()
+		d      = s.GetDialect()
+	)
+
+	if len(fields) == 0 {
+		fields = table.Fields()
+	}
+
+	for _, f := range fields {
+		if table.IsMeta(f) || table.IsJSONB(f) {
+			object.add(f, getQualifiedFallbackColumn(d, `"rel"`, f, lang, s.fallback))
+		} else if parent, innerField, ok := table.GetInnerField(f); ok {
+			column := getQualifiedFallbackColumn(d, `"rel"`, parent, lang, s.fallback)
+			if IsFieldRelations(parent) { // relations are stored within data
+				parent = FieldData
+			}
+			object.add(parent+"."+innerField, d.JSONAccessor(column, innerField, false))
+		}
+	}
+
+	return object.SQL(d)
+}
@@ -1,28 +1,49 @@
 package somesql
 
 import (
+	"context"
 	"database/sql"
 	"encoding/json"
-	"strconv"
+	"errors"
+	"fmt"
 	"strings"
 )
 
-// Insert generates Postgres INSERT statement
-// Implements: Mutator
+// On conflict actions
+const (
+	// ConflictNone raises an error on conflict (default)
+	ConflictNone uint8 = iota
+	// ConflictDoNothing skips conflicting rows
+	ConflictDoNothing
+	// ConflictOverwrite replaces conflicting rows with incoming values
+	ConflictOverwrite
+	// ConflictMerge replaces meta fields and merges incoming JSONB fields into existing ones
+	ConflictMerge
+)
+
+// Insert generates INSERT statement of its Dialect (Postgres by default)
+// Implements: Mutator, Returner
 type Insert struct {
-	fields Fields
-	sql    string
-	values []interface{}
-	db     *sql.DB
-	lang   string
+	fields         []Fields
+	conflict       uint8
+	conflictFields []string
+	returning      []string
+	sql            string
+	values         []interface{}
+	db             *sql.DB
+	lang           string
+	table          Table
+	dialect        Dialect
+	err            error
 }
 
 // NewInsert returns a new Insert
 func NewInsert(lang string, db ...*sql.DB) *Insert {
 	var s Insert
 
-	s.fields = NewFields()
 	s.lang = lang
+	s.table = TableRepo
+	s.dialect = DefaultDialect
 
 	if len(db) > 0 {
 		s.db = db[0]
@@ -51,6 +72,30 @@ func (s Insert) GetLang() string {
 	return s.lang
 }
 
+// SetTable implements Statement
+func (s *Insert) SetTable(t Table) {
+	s.table = t
+}
+
+// GetTable implements Statement
+func (s Insert) GetTable() Table {
+	return s.table
+}
+
+// SetDialect implements Statement
+func (s *Insert) SetDialect(d Dialect) {
+	s.dialect = d
+}
+
+// GetDialect implements Statement
+func (s Insert) GetDialect() Dialect {
+	if s.dialect == nil {
+		return DefaultDialect
+	}
+
+	return s.dialect
+}
+
 // GetSQL implements Statement
 func (s Insert) GetSQL() string {
 	return s.sql
@@ -62,79 +107,378 @@ func (s Insert) GetValues() []interface{} {
 }
 
 // ToSQL implements Statement
+// All rows are part of the statement, Exec splits them into chunks of MaxPlaceholders values at most
 func (s *Insert) ToSQL() {
+	sql, values, rowsErrs := s.build(s.fields)
+
+	s.sql, s.values = sql, values
+	s.err = newBuildError(append(s.check(), rowsErrs...))
+}
+
+// Build implements Statement
+func (s *Insert) Build() (string, []interface{}, error) {
+	s.ToSQL()
+
+	return s.GetSQL(), s.GetValues(), s.err
+}
+
+// check returns the errors of the language, columns, conflict and returning fields of Insert
+// errors of the rows are returned by build
+func (s Insert) check() []error {
+	var (
+		table = s.GetTable()
+		errs  = checkLangs(table, s.GetLang())
+	)
+
+	if len(s.columns(s.fields)) == 0 {
+		errs = append(errs, fmt.Errorf("%w: VALUES", ErrEmptyClause))
+	}
+
+	for _, f := range append(append([]string(nil), s.conflictFields...), s.returning...) {
+		errs = appendError(errs, checkField(table, f))
+	}
+
+	return errs
+}
+
+// build returns the INSERT statement, values and errors for rows
+func (s Insert) build(rows []Fields) (string, []interface{}, []error) {
 	var (
 		fieldsStr        string
-		placesholdersStr string
 		placeholderIndex int
-		dataFieldLang    string
+		table            = s.GetTable()
+		d                = s.GetDialect()
+		columns          = s.columns(rows)
+		values           = make([]interface{}, 0)
+		errs             []error
 
-		fieldsBuff       strings.Builder
-		placeholdersBuff strings.Builder
+		fieldsBuff strings.Builder
+		rowsBuff   strings.Builder
 	)
 
-	dataFieldLang = GetLangFieldData(s.GetLang())
+	if len(rows) == 0 {
+		rows = []Fields{NewFields()}
+	}
+
+	// Double quote the field name
+	for _, f := range columns {
+		f = GetLangField(f, s.GetLang()) // data => data_<lang>
+		fieldsBuff.WriteString(quoteIdentifier(f) + `, `)
+	}
+
+	if fieldsBuff.Len() > 0 {
+		fieldsStr = fieldsBuff.String()[:fieldsBuff.Len()-2] // trim ", "
+	}
+
+	// Processing values and placeholders of each row
+	for _, row := range rows {
+		var (
+			placeholdersBuff strings.Builder
+			rowValues        = make(map[string]interface{})
+		)
 
-	fields, values := s.fields.List()
+		errs = append(errs, checkFields(table, row)...)
 
-	// Processing fields and values
-	s.values = make([]interface{}, len(fields))
-	for i, f := range fields {
-		if IsFieldMeta(f) {
-			s.values[i] = values[i]
-		} else if IsFieldData(f) || IsFieldRelations(f) {
-			if jsonbFields, ok := values[i].(JSONBFields); ok {
+		fields, vals := row.ListOf(table)
+		for i, f := range fields {
+			rowValues[f] = vals[i]
+		}
+
+		for _, f := range columns {
+			v, ok := rowValues[f]
+			if !ok && d.Default() == "" {
+				errs = append(errs, FieldError{Field: f, Err: fmt.Errorf("%w: missing within a row without DEFAULT (rows are split by Exec)", ErrValuesMismatch)})
+				placeholdersBuff.WriteString(`NULL, `)
+				continue
+			} else if !ok {
+				placeholdersBuff.WriteString(d.Default() + `, `)
+				continue
+			}
+
+			if jsonbFields, ok := v.(JSONBFields); ok && table.IsJSONB(f) {
+				v = nil
 				if jsonBytes, err := json.Marshal(jsonbFields.Values()); err == nil {
-					s.values[i] = string(jsonBytes)
+					v = string(jsonBytes)
+				} else {
+					errs = append(errs, FieldError{Field: f, Err: fmt.Errorf("%w: %v", ErrInvalidJSON, err)})
 				}
 			}
-		}
 
-		// Double quote the field name
-		// Placeholders
-		if IsFieldMeta(f) || IsFieldData(f) || IsFieldRelations(f) {
-			if IsFieldData(f) || IsFieldRelations(f) {
-				f = dataFieldLang // data => data_<lang>
-			}
-			fieldsBuff.WriteString(`"` + f + `", `)
+			values = append(values, v)
 			placeholderIndex++
-			placeholdersBuff.WriteString(`$` + strconv.Itoa(placeholderIndex) + `, `)
+			placeholdersBuff.WriteString(d.Placeholder(placeholderIndex) + `, `)
+		}
+
+		placeholdersStr := placeholdersBuff.String()
+		if placeholdersBuff.Len() > 0 {
+			placeholdersStr = placeholdersStr[:placeholdersBuff.Len()-2] // trim ", "
 		}
+		rowsBuff.WriteString(`(` + placeholdersStr + `), `)
 	}
 
-	if fieldsBuff.Len() > 0 {
-		fieldsStr = fieldsBuff.String()[:fieldsBuff.Len()-2] // trim ", "
+	rowsStr := rowsBuff.String()[:rowsBuff.Len()-2] // trim ", "
+
+	sql := "INSERT INTO " + table.Name + " (" + fieldsStr + ") VALUES " + rowsStr + " " + s.onConflict(columns) + " " + processReturning(d, table, s.GetLang(), s.returning)
+
+	return cleanStatement(sql), values, errs
+}
+
+// onConflict returns the ON CONFLICT clause of the statement
+func (s Insert) onConflict(columns []string) string {
+	var (
+		conflictStr string
+		table       = s.GetTable()
+
+		targetBuff strings.Builder
+		setBuff    strings.Builder
+	)
+
+	if s.conflict == ConflictNone {
+		return ""
+	}
+
+	isTarget := make(map[string]bool)
+	for _, f := range s.conflictFields {
+		targetBuff.WriteString(quoteIdentifier(f) + `, `)
+		isTarget[f] = true
+	}
+
+	conflictStr = "ON CONFLICT (" + targetBuff.String()[:targetBuff.Len()-2] + ")" // trim ", "
+
+	if s.conflict == ConflictDoNothing {
+		return conflictStr + " DO NOTHING"
+	}
+
+	for _, f := range columns {
+		if isTarget[f] {
+			continue
+		}
+
+		column := quoteIdentifier(GetLangField(f, s.GetLang()))
+		if s.conflict == ConflictMerge && table.IsJSONB(f) {
+			setBuff.WriteString(column + ` = ` + s.GetDialect().JSONMerge(table.Name+`.`+column, `EXCLUDED.`+column) + `, `)
+		} else {
+			setBuff.WriteString(column + ` = EXCLUDED.` + column + `, `)
+		}
+	}
+
+	if setBuff.Len() == 0 {
+		return conflictStr + " DO NOTHING"
+	}
+
+	return conflictStr + " DO UPDATE SET " + setBuff.String()[:setBuff.Len()-2] // trim ", "
+}
+
+// columns returns the fields set in any of the rows, in table order
+func (s Insert) columns(rows []Fields) []string {
+	var (
+		table   = s.GetTable()
+		columns []string
+		isSet   = make(map[string]bool)
+	)
+
+	for _, row := range rows {
+		fields, _ := row.ListOf(table)
+		for _, f := range fields {
+			isSet[f] = true
+		}
 	}
 
-	if placeholdersBuff.Len() > 0 {
-		placesholdersStr = placeholdersBuff.String()[:placeholdersBuff.Len()-2] // trim ", "
+	for _, f := range append(table.Fields(), FieldRelations) {
+		if isSet[f] {
+			columns = append(columns, f)
+		}
 	}
 
-	sql := "INSERT INTO " + Table + " (" + fieldsStr + ") VALUES (" + placesholdersStr + ")"
+	return columns
+}
+
+// statements returns the INSERT statements for all rows along with the errors of the rows
+// rows are split into chunks so that each statement has MaxPlaceholders values at most
+func (s Insert) statements() ([]statement, error) {
+	var (
+		stmts []statement
+		errs  = s.check()
+	)
+
+	for _, rows := range s.rowGroups() {
+		size := len(rows)
+		if numColumns := len(s.columns(rows)); numColumns > 0 && len(rows)*numColumns > MaxPlaceholders {
+			size = MaxPlaceholders / numColumns
+		}
 
-	s.sql = cleanStatement(sql)
+		for i := 0; i < len(rows); i += size {
+			end := i + size
+			if end > len(rows) {
+				end = len(rows)
+			}
+
+			sql, values, chunkErrs := s.build(rows[i:end])
+			stmts = append(stmts, statement{sql: sql, values: values})
+			errs = append(errs, chunkErrs...)
+		}
+	}
+
+	return stmts, newBuildError(errs)
+}
+
+// rowGroups returns the rows of Insert grouped by set of columns, in order, if the dialect has no DEFAULT
+// columns missing within a row would be NULL rather than their default
+func (s Insert) rowGroups() [][]Fields {
+	if s.GetDialect().Default() != "" {
+		return [][]Fields{s.fields}
+	}
+
+	var (
+		groups [][]Fields
+		index  = make(map[string]int)
+	)
+
+	for _, row := range s.fields {
+		key := strings.Join(s.columns([]Fields{row}), ",")
+
+		i, ok := index[key]
+		if !ok {
+			i = len(groups)
+			index[key] = i
+			groups = append(groups, nil)
+		}
+		groups[i] = append(groups[i], row)
+	}
+
+	return groups
 }
 
 // Exec implements Mutator
+// All chunks are executed within the same transaction
 func (s Insert) Exec(autocommit bool) error {
-	if s.GetSQL() == "" || len(s.GetValues()) == 0 {
-		s.ToSQL()
+	return s.ExecContext(context.Background(), autocommit)
+}
+
+// ExecContext implements Mutator
+// All chunks are executed within the same transaction
+func (s Insert) ExecContext(ctx context.Context, autocommit bool) error {
+	stmts, err := s.statements()
+	if err != nil {
+		return err
 	}
 
-	return exec(s.GetSQL(), s.GetValues(), s.GetDB(), autocommit)
+	return execStatements(ctx, stmts, s.GetDB(), autocommit)
 }
 
 // ExecTx implements Mutator
 func (s Insert) ExecTx(tx *sql.Tx, autocommit bool) error {
-	if s.GetSQL() == "" || len(s.GetValues()) == 0 {
-		s.ToSQL()
+	return s.ExecTxContext(context.Background(), tx, autocommit)
+}
+
+// ExecTxContext implements Mutator
+func (s Insert) ExecTxContext(ctx context.Context, tx *sql.Tx, autocommit bool) error {
+	stmts, err := s.statements()
+	if err != nil {
+		return err
 	}
 
-	return execTx(s.GetSQL(), s.GetValues(), tx, autocommit)
+	return execStatementsTx(ctx, stmts, tx, autocommit)
+}
+
+// Into sets the table for Insert
+func (s *Insert) Into(t Table) *Insert {
+	s.SetTable(t)
+	return s
 }
 
 // Fields sets the fields and values for insert
 func (s *Insert) Fields(fields Fields) *Insert {
-	s.fields = fields
+	s.fields = []Fields{fields}
 	return s
 }
+
+// OnConflict sets the action to take when a row conflicts with an existing one on fields (defaults to id)
+// ConflictDoNothing, ConflictOverwrite or ConflictMerge
+func (s *Insert) OnConflict(action uint8, fields ...string) *Insert {
+	if len(fields) == 0 {
+		fields = []string{FieldID}
+	}
+
+	s.conflict = action
+	s.conflictFields = fields
+	return s
+}
+
+// Batch adds rows of fields and values for insert
+func (s *Insert) Batch(fields ...Fields) *Insert {
+	s.fields = append(s.fields, fields...)
+	return s
+}
+
+// ExecReturning implements Returner
+// Fields of the table are returned unless set with Returning
+func (s Insert) ExecReturning(autocommit bool) ([]Document, error) {
+	return s.ExecReturningContext(context.Background(), autocommit)
+}
+
+// ExecReturningContext implements Returner
+// Fields of the table are returned unless set with Returning
+func (s Insert) ExecReturningContext(ctx context.Context, autocommit bool) ([]Document, error) {
+	s.useDefaultReturning()
+
+	stmts, err := s.statements()
+	if err != nil {
+		return nil, err
+	}
+
+	return execReturning(ctx, stmts, s.GetDB(), autocommit, s.GetLang())
+}
+
+// ExecTxReturning implements Returner
+// Fields of the table are returned unless set with Returning
+func (s Insert) ExecTxReturning(tx *sql.Tx, autocommit bool) ([]Document, error) {
+	return s.ExecTxReturningContext(context.Background(), tx, autocommit)
+}
+
+// ExecTxReturningContext implements Returner
+// Fields of the table are returned unless set with Returning
+func (s Insert) ExecTxReturningContext(ctx context.Context, tx *sql.Tx, autocommit bool) ([]Document, error) {
+	s.useDefaultReturning()
+
+	stmts, err := s.statements()
+	if err != nil {
+		return nil, err
+	}
+
+	return execReturningTx(ctx, stmts, tx, autocommit, s.GetLang())
+}
+
+// RowsTx implements Returner
+// Fields of the table are returned unless set with Returning
+func (s Insert) RowsTx(tx *sql.Tx) (*sql.Rows, error) {
+	return s.RowsTxContext(context.Background(), tx)
+}
+
+// RowsTxContext implements Returner
+// Fields of the table are returned unless set with Returning
+func (s Insert) RowsTxContext(ctx context.Context, tx *sql.Tx) (*sql.Rows, error) {
+	s.useDefaultReturning()
+	stmts, err := s.statements()
+	if err != nil {
+		return nil, err
+	}
+	if len(stmts) > 1 {
+		return nil, errors.New("too many values for a single statement")
+	}
+
+	return rowsTx(ctx, stmts[0].sql, stmts[0].values, tx)
+}
+
+// Returning sets the fields returned by Insert
+func (s *Insert) Returning(fields ...string) *Insert {
+	s.returning = fields
+	return s
+}
+
+// useDefaultReturning returns all fields of the table if none were set
+func (s *Insert) useDefaultReturning() {
+	if len(s.returning) == 0 {
+		s.returning = s.GetTable().Fields()
+	}
+}
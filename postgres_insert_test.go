@@ -0,0 +1,43 @@
+package somesql
+
+import (
+	"fmt"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInsertStatements(t *testing.T) {
+	batch := func(n int) []Fields {
+		rows := make([]Fields, n)
+		for i := range rows {
+			rows[i] = NewFields().ID(strconv.Itoa(i)).Type("entityA").OwnerID("0")
+		}
+		return rows
+	}
+
+	tests := []struct {
+		name   string
+		rows   int
+		chunks []int // rows per chunk
+	}{
+		{name: "single row", rows: 1, chunks: []int{1}},
+		{name: "below limit", rows: MaxPlaceholders / 3, chunks: []int{MaxPlaceholders / 3}},
+		{name: "above limit", rows: MaxPlaceholders/3 + 1, chunks: []int{MaxPlaceholders / 3, 1}},
+		{name: "many chunks", rows: 50000, chunks: []int{MaxPlaceholders / 3, MaxPlaceholders / 3, 50000 - 2*(MaxPlaceholders/3)}},
+	}
+
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stmts, err := NewInsert("en").Batch(batch(tt.rows)...).statements()
+
+			assert.Nil(t, err, fmt.Sprintf("%d: error", i+1))
+			assert.Len(t, stmts, len(tt.chunks), fmt.Sprintf("%d: invalid number of chunks", i+1))
+			for c, stmt := range stmts {
+				assert.Len(t, stmt.values, tt.chunks[c]*3, fmt.Sprintf("%d: invalid number of values in chunk %d", i+1, c+1))
+				assert.True(t, len(stmt.values) <= MaxPlaceholders, fmt.Sprintf("%d: too many values in chunk %d", i+1, c+1))
+			}
+		})
+	}
+}
@@ -1,12 +1,29 @@
 package somesql
 
 import (
+	"context"
 	"database/sql"
 	"errors"
 )
 
 func exec(sql string, values []interface{}, db *sql.DB, autocommit bool) error {
-	tx, err := db.Begin()
+	return execContext(context.Background(), sql, values, db, autocommit)
+}
+
+func execContext(ctx context.Context, sql string, values []interface{}, db *sql.DB, autocommit bool) error {
+	return execStatements(ctx, []statement{{sql: sql, values: values}}, db, autocommit)
+}
+
+func execTx(sql string, values []interface{}, tx *sql.Tx, autocommit bool) error {
+	return execTxContext(context.Background(), sql, values, tx, autocommit)
+}
+
+func execTxContext(ctx context.Context, sql string, values []interface{}, tx *sql.Tx, autocommit bool) error {
+	return execStatementsTx(ctx, []statement{{sql: sql, values: values}}, tx, autocommit)
+}
+
+func execStatements(ctx context.Context, stmts []statement, db *sql.DB, autocommit bool) error {
+	tx, err := db.BeginTx(ctx, nil)
 	if err != nil {
 		return err
 	}
@@ -16,32 +33,85 @@ func exec(sql string, values []interface{}, db *sql.DB, autocommit bool) error {
 		}
 	}()
 
-	err = execTx(sql, values, tx, autocommit)
+	err = execStatementsTx(ctx, stmts, tx, autocommit)
 
 	return err
 }
 
-func execTx(sql string, values []interface{}, tx *sql.Tx, autocommit bool) error {
-	if sql == "" || len(values) == 0 {
-		return errors.New("invalid sql or values")
+func execStatementsTx(ctx context.Context, stmts []statement, tx *sql.Tx, autocommit bool) error {
+	for _, s := range stmts {
+		if s.sql == "" || len(s.values) == 0 {
+			return errors.New("invalid sql or values")
+		}
+
+		stmt, err := tx.PrepareContext(ctx, s.sql)
+		if err != nil {
+			return err
+		}
+
+		_, err = stmt.ExecContext(ctx, s.values...)
+		stmt.Close()
+		if err != nil {
+			return err
+		}
 	}
 
-	stmt, err := tx.Prepare(sql)
-	if err != nil {
-		return err
+	if autocommit {
+		if err := tx.Commit(); err != nil {
+			return err
+		}
 	}
-	defer stmt.Close()
 
-	_, err = stmt.Exec(values...)
+	return nil
+}
+
+func execReturning(ctx context.Context, stmts []statement, db *sql.DB, autocommit bool, lang string) ([]Document, error) {
+	tx, err := db.BeginTx(ctx, nil)
 	if err != nil {
-		return err
+		return nil, err
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+		}
+	}()
+
+	docs, err := execReturningTx(ctx, stmts, tx, autocommit, lang)
+
+	return docs, err
+}
+
+func execReturningTx(ctx context.Context, stmts []statement, tx *sql.Tx, autocommit bool, lang string) ([]Document, error) {
+	docs := make([]Document, 0)
+
+	for _, s := range stmts {
+		rows, err := rowsTx(ctx, s.sql, s.values, tx)
+		if err != nil {
+			return nil, err
+		}
+
+		err = scanDocuments(rows, lang, func(d Document) error {
+			docs = append(docs, d)
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
 	}
 
 	if autocommit {
 		if err := tx.Commit(); err != nil {
-			return err
+			return nil, err
 		}
 	}
 
-	return nil
+	return docs, nil
+}
+
+func rowsTx(ctx context.Context, sql string, values []interface{}, tx *sql.Tx) (*sql.Rows, error) {
+	if sql == "" || len(values) == 0 {
+		return nil, errors.New("invalid sql or values")
+	}
+
+	return tx.QueryContext(ctx, sql, values...)
 }
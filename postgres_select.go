@@ -1,12 +1,14 @@
 package somesql
 
 import (
+	"context"
 	"database/sql"
+	"fmt"
 	"strconv"
 	"strings"
 )
 
-// Select generates Postgres SELECT statement
+// Select generates SELECT statement of its Dialect (Postgres by default)
 // Implements: Accessor
 type Select struct {
 	fields     []string
@@ -14,25 +16,65 @@ type Select struct {
 	inner      bool
 	offset     int
 	limit      int
+	hasLimit   bool // limit set with Limit, kept within sub-queries
 	order      []order
 	sql        string
 	values     []interface{}
 	db         *sql.DB
 	lang       string
+	table      Table
+	aggregates []aggregate
+	groupBy    []string
+	having     []Condition
+	distinct   bool
+	keyset     bool
+	cursor     []interface{}
+	includes   []include
+	headlines  []headline
+	fallback   []string
+	langs      []string
+	dialect    Dialect
+	alias      string
+	errs       []error // errors of the builder methods
+	err        error   // errors of the last ToSQL
 }
 
 type order struct {
+	field  string
+	order  bool
+	search *ConditionSearch
+}
+
+type headline struct {
 	field string
-	order bool
+	query string
+	alias string
+}
+
+// Aggregate functions
+const (
+	AggCount = "COUNT"
+	AggSum   = "SUM"
+	AggAvg   = "AVG"
+	AggMin   = "MIN"
+	AggMax   = "MAX"
+)
+
+type aggregate struct {
+	function string
+	field    string
+	alias    string
+	distinct bool
 }
 
 // NewSelect returns a new Select
 func NewSelect(lang string, db ...*sql.DB) *Select {
 	var s Select
 
-	s.fields = FieldsList
 	s.limit = 10
 	s.lang = lang
+	s.table = TableRepo
+	s.dialect = DefaultDialect
 
 	if len(db) > 0 {
 		s.db = db[0]
@@ -70,6 +112,30 @@ func (s Select) GetLang() string {
 	return s.lang
 }
 
+// SetTable implements Statement
+func (s *Select) SetTable(t Table) {
+	s.table = t
+}
+
+// GetTable implements Statement
+func (s Select) GetTable() Table {
+	return s.table
+}
+
+// SetDialect implements Statement
+func (s *Select) SetDialect(d Dialect) {
+	s.dialect = d
+}
+
+// GetDialect implements Statement
+func (s Select) GetDialect() Dialect {
+	if s.dialect == nil {
+		return DefaultDialect
+	}
+
+	return s.dialect
+}
+
 // GetSQL implements Statement
 func (s Select) GetSQL() string {
 	return s.sql
@@ -89,75 +155,71 @@ func (s *Select) ToSQL() {
 		limitStr      string
 		orderStr      string
 		isInnerQuery  = s.IsInner()
-		dataFieldLang string
+		lang          = s.GetLang()
+		table         = s.GetTable()
+		d             = s.GetDialect()
 
-		fieldsBuff     strings.Builder
-		metaFieldsBuff strings.Builder
-		dataFieldsBuff strings.Builder
-		relFieldsBuff  strings.Builder
-		orderBuff      strings.Builder
-	)
+		groupByStr string
+		havingStr  string
+		lateralStr string
 
-	dataFieldLang = GetLangFieldData(s.GetLang())
+		orderBuff strings.Builder
+		errs      = append([]error(nil), s.errs...)
+	)
 
-	// Processing fields
-	for _, f := range s.fields {
-		if IsFieldMeta(f) || IsFieldData(f) {
-			if f == FieldData {
-				f = dataFieldLang
-			}
-			metaFieldsBuff.WriteString(`"` + f + `", `)
-		} else if innerField, ok := GetInnerField(FieldData, f); ok {
-			if isInnerQuery {
-				dataFieldsBuff.WriteString(`"` + dataFieldLang + `"->>'` + innerField + `' "` + innerField + `", `)
-			} else {
-				dataFieldsBuff.WriteString(`'` + innerField + `', "` + dataFieldLang + `"->'` + innerField + `', `)
-			}
-		} else if innerField, ok := GetInnerField(FieldRelations, f); ok {
-			if isInnerQuery {
-				dataFieldsBuff.WriteString(`"` + dataFieldLang + `"->>'` + innerField + `' "` + innerField + `", `)
-			} else {
-				dataFieldsBuff.WriteString(`'` + innerField + `', "` + dataFieldLang + `"->'` + innerField + `', `)
-			}
-		}
-	}
+	s.values = make([]interface{}, 0)
 
-	// Put everything back in order
-	// Meta fields
-	if metaFieldsBuff.Len() > 0 {
-		metaFieldsStr := metaFieldsBuff.String()[:metaFieldsBuff.Len()-2] // trim ", "
-		fieldsBuff.WriteString(metaFieldsStr + `, `)
-	}
+	errs = append(errs, s.check()...)
+	errs = append(errs, checkConditions(s.conditions, table)...)
+	havingConds := withContext(s.having, s.havingContext())
+	errs = append(errs, checkConditions(havingConds, s.havingTable())...)
 
-	// Data fields
-	if dataFieldsBuff.Len() > 0 {
-		dataFieldsStr := dataFieldsBuff.String()[:dataFieldsBuff.Len()-2] // trim ", "
-		if isInnerQuery {
-			fieldsBuff.WriteString(dataFieldsStr + `, `)
+	if s.isAggregated() {
+		fieldsStr, groupByStr, lateralStr = s.processAggregates()
+	} else {
+		if s.langs != nil && !isInnerQuery {
+			fieldsStr = s.processLangsFields()
 		} else {
-			fieldsBuff.WriteString(`json_build_object(` + dataFieldsStr + `) "` + FieldData + `", `)
+			fieldsStr = processFields(d, table, lang, s.fields, isInnerQuery, s.fallback...)
 		}
+		if len(s.fallback) > 0 && !isInnerQuery {
+			fieldsStr += `, ` + getFallbackLang(d, lang, s.fallback) + ` "` + FieldDataLang + `"`
+		}
+
+		includesStr, includesLateralStr := s.processIncludes()
+		fieldsStr += includesStr
+		lateralStr = includesLateralStr
+
+		headlinesStr, headlinesValues := s.processHeadlines()
+		fieldsStr += headlinesStr
+		s.values = append(s.values, headlinesValues...)
 	}
 
-	// Relationship fields
-	if relFieldsBuff.Len() > 0 {
-		relFieldsStr := relFieldsBuff.String()[:relFieldsBuff.Len()-2] // trim ", "
-		if isInnerQuery {
-			fieldsBuff.WriteString(relFieldsStr + `, `)
+	conditions, condValues := processConditions(withContext(s.conditions, s.conditionContext()))
+	s.values = append(s.values, condValues...)
+
+	if s.keyset && s.cursor != nil {
+		keyset, keysetValues, err := s.keysetCondition()
+		if err != nil {
+			errs = append(errs, err)
+		}
+		if len(conditions) > 0 {
+			conditions = "(" + conditions + ") AND " + keyset
 		} else {
-			fieldsBuff.WriteString(`json_build_object(` + relFieldsStr + `) "` + FieldRelations + `", `)
+			conditions = keyset
 		}
+		s.values = append(s.values, keysetValues...)
 	}
 
-	if fieldsBuff.Len() > 0 {
-		fieldsStr = fieldsBuff.String()[:fieldsBuff.Len()-2] // trim ", "
+	if len(conditions) > 0 {
+		conditionsStr = "WHERE " + conditions
 	}
 
-	conditions, condValues := processConditions(s.conditions)
-	s.values = condValues
+	having, havingValues := processConditions(havingConds)
+	s.values = append(s.values, havingValues...)
 
-	if len(conditions) > 0 {
-		conditionsStr = "WHERE " + conditions
+	if len(having) > 0 {
+		havingStr = "HAVING " + having
 	}
 
 	if s.limit > 0 {
@@ -168,34 +230,436 @@ func (s *Select) ToSQL() {
 		offsetStr = "OFFSET " + strconv.Itoa(s.offset)
 	}
 
-	if len(s.order) > 0 {
+	orders := s.order
+	if s.keyset {
+		orders = s.keysetOrder()
+	}
+
+	if len(orders) > 0 {
 		orderBuff.WriteString("ORDER BY ")
 
-		for _, o := range s.order {
+		for _, o := range orders {
 			orderStr := "DESC"
 			if o.order {
 				orderStr = "ASC"
 			}
 
-			if IsFieldMeta(o.field) {
-				orderBuff.WriteString(o.field + " " + orderStr + `, `)
-			} else if IsFieldData(o.field) {
-				orderBuff.WriteString(dataFieldLang + " " + orderStr + `, `)
-			} else {
-				orderBuff.WriteString(`"` + dataFieldLang + `"->>'` + o.field + `' ` + orderStr + `, `)
+			if o.search != nil { // rank of search
+				orderBuff.WriteString(d.SearchRank(lang, getSearchText(d, lang, s.fallback, o.search.Fields)) + ` ` + orderStr + `, `)
+				s.values = append(s.values, o.search.Query)
+				continue
+			}
+
+			if s.keyset { // as expected by keysetCondition, whatever the dialect
+				orderStr += " NULLS FIRST"
 			}
+
+			orderBuff.WriteString(s.orderExpression(o.field) + ` ` + orderStr + `, `)
 		}
 
 		orderStr = orderBuff.String()[:orderBuff.Len()-2]
 	}
 
-	sql := "SELECT " + fieldsStr + " FROM " + Table + " " + conditionsStr + " " + orderStr + " " + limitStr + " " + offsetStr
+	sql := "SELECT " + fieldsStr + " FROM " + getTableClause(table, s.alias) + " " + lateralStr + " " + conditionsStr + " " + groupByStr + " " + havingStr + " " + orderStr + " " + limitStr + " " + offsetStr
+
+	if len(errs) == 0 {
+		if err := checkPlaceholders(sql, s.values); err != nil {
+			errs = append(errs, err)
+		}
+	}
 
 	if !isInnerQuery {
-		sql = processPlaceholders(sql)
+		sql = d.Placeholders(sql)
 	}
 
 	s.sql = cleanStatement(sql)
+	s.err = newBuildError(errs)
+}
+
+// Build implements Statement
+func (s *Select) Build() (string, []interface{}, error) {
+	s.ToSQL()
+
+	return s.GetSQL(), s.GetValues(), s.err
+}
+
+// check returns the errors of the languages, fields and aliases of Select
+func (s Select) check() []error {
+	var (
+		table  = s.GetTable()
+		errs   = checkLangs(table, append(append([]string{s.GetLang()}, s.fallback...), s.langs...)...)
+		fields = append(append([]string(nil), s.fields...), s.groupBy...)
+	)
+
+	for _, a := range s.aggregates {
+		if a.field != "" {
+			fields = append(fields, a.field)
+		}
+		switch a.function {
+		case AggCount, AggSum, AggAvg, AggMin, AggMax:
+		default:
+			errs = append(errs, FieldError{Field: a.alias, Err: fmt.Errorf("%w: %q", ErrInvalidFunction, a.function)})
+		}
+		errs = appendError(errs, checkIdentifier(a.alias, a.alias))
+	}
+
+	for _, h := range s.headlines {
+		fields = append(fields, h.field)
+		errs = appendError(errs, checkIdentifier(h.alias, h.alias))
+	}
+
+	for _, inc := range s.includes {
+		fields = append(fields, inc.fields...)
+	}
+
+	for _, o := range s.order {
+		if o.search != nil {
+			if len(o.search.Fields) == 0 {
+				errs = append(errs, fmt.Errorf("%w: rank of search without fields", ErrEmptyClause))
+			}
+			fields = append(fields, o.search.Fields...)
+		} else if _, ok := s.aggregateAlias(o.field); ok {
+			continue
+		} else if checkField(table, o.field) != nil && !strings.Contains(o.field, ".") && len(table.JSONBFields) > 0 {
+			// defaults to inner field of first JSONB field (see orderExpression)
+			errs = appendError(errs, checkIdentifier(o.field, o.field))
+		} else {
+			fields = append(fields, o.field)
+		}
+	}
+
+	for _, f := range fields {
+		errs = appendError(errs, checkField(table, f))
+	}
+
+	if s.alias != "" {
+		errs = appendError(errs, checkIdentifier(s.alias, s.alias))
+	}
+
+	return appendError(errs, s.checkKeyset())
+}
+
+// havingTable returns the table Having conditions are checked against, aliases of aggregates being fields
+func (s Select) havingTable() Table {
+	table := s.GetTable()
+
+	table.MetaFields = append([]string(nil), table.MetaFields...)
+	for _, a := range s.aggregates {
+		table.MetaFields = append(table.MetaFields, a.alias)
+	}
+
+	return table
+}
+
+// processLangsFields returns the projection of fields with data fields of each lang of Select.Langs
+// data of each lang is named translations.<lang>
+func (s Select) processLangsFields() string {
+	var (
+		fieldsBuff  strings.Builder
+		table       = s.GetTable()
+		d           = s.GetDialect()
+		langs       = s.langs
+		fields      = s.fields
+		metaFields  []string
+		innerFields []string
+		isWhole     bool
+	)
+
+	if len(langs) == 0 {
+		langs = table.Langs
+	}
+
+	if len(fields) == 0 {
+		fields = table.Fields()
+	}
+
+	for _, f := range fields {
+		if IsFieldData(f) || IsFieldRelations(f) {
+			isWhole = true
+		} else if innerField, ok := GetInnerField(FieldData, f); ok {
+			innerFields = append(innerFields, innerField)
+		} else if innerField, ok := GetInnerField(FieldRelations, f); ok {
+			innerFields = append(innerFields, innerField)
+		} else {
+			metaFields = append(metaFields, f)
+		}
+	}
+
+	if len(metaFields) > 0 {
+		fieldsBuff.WriteString(processFields(d, table, s.GetLang(), metaFields, false) + `, `)
+	}
+
+	for _, l := range langs {
+		alias := ` ` + quoteIdentifier(FieldTranslations+`.`+l) + `, `
+		if isWhole {
+			fieldsBuff.WriteString(quoteIdentifier(GetLangFieldData(l)) + alias)
+			continue
+		}
+
+		if len(innerFields) > 0 {
+			var object jsonbObject
+			for _, innerField := range innerFields {
+				object.add(innerField, d.JSONAccessor(quoteIdentifier(GetLangFieldData(l)), innerField, false))
+			}
+			fieldsBuff.WriteString(object.SQL(d) + alias)
+		}
+	}
+
+	if fieldsBuff.Len() == 0 {
+		return ""
+	}
+
+	return fieldsBuff.String()[:fieldsBuff.Len()-2] // trim ", "
+}
+
+// processHeadlines returns the projection and values of search headlines
+func (s Select) processHeadlines() (string, []interface{}) {
+	var (
+		fieldsBuff strings.Builder
+		values     []interface{}
+		lang       = s.GetLang()
+		d          = s.GetDialect()
+	)
+
+	for _, h := range s.headlines {
+		fieldsBuff.WriteString(`, ` + d.SearchHeadline(lang, getSearchField(d, lang, s.fallback, h.field)) + ` ` + quoteIdentifier(h.alias))
+		values = append(values, h.query)
+	}
+
+	return fieldsBuff.String(), values
+}
+
+// conditionContext returns the context conditions of Select are rendered within
+func (s Select) conditionContext() conditionContext {
+	return conditionContext{fallback: s.fallback, dialect: s.GetDialect()}
+}
+
+// havingContext returns the context Having conditions are rendered within
+// aliases of aggregates are rendered as their expression, output columns being unknown to HAVING
+func (s Select) havingContext() conditionContext {
+	ctx := s.conditionContext()
+
+	ctx.aggregates = make(map[string]string, len(s.aggregates))
+	for _, a := range s.aggregates {
+		ctx.aggregates[a.alias] = s.aggregateExpression(a)
+	}
+
+	return ctx
+}
+
+// isAggregated returns true if rows are aggregated, grouped or distinct
+func (s Select) isAggregated() bool {
+	return len(s.aggregates) > 0 || len(s.groupBy) > 0 || s.distinct
+}
+
+// processAggregates returns the projection, GROUP BY clause and lateral joins of an aggregated Select
+// inner fields are projected as text named after the inner field
+// elements of relations arrays are joined laterally, one row per element
+func (s Select) processAggregates() (string, string, string) {
+	var (
+		fieldsStr  string
+		groupByStr string
+
+		fieldsBuff  strings.Builder
+		groupByBuff strings.Builder
+		lateralBuff strings.Builder
+		isJoined    = make(map[string]bool)
+		fields      = s.fields
+	)
+
+	if len(fields) == 0 {
+		fields = s.groupBy
+	}
+	if len(fields) == 0 && len(s.aggregates) == 0 {
+		fields = s.GetTable().Fields()
+	}
+
+	expression := func(field string) (string, string) {
+		expr, alias, join := getAggregateField(s.GetDialect(), s.GetTable(), s.GetLang(), s.fallback, field)
+		if join != "" && !isJoined[alias] {
+			isJoined[alias] = true
+			lateralBuff.WriteString(join + ` `)
+		}
+		return expr, alias
+	}
+
+	for _, f := range fields {
+		expr, alias := expression(f)
+		if alias != "" && expr != quoteIdentifier(alias) {
+			expr += ` ` + quoteIdentifier(alias)
+		}
+		fieldsBuff.WriteString(expr + `, `)
+	}
+
+	for _, a := range s.aggregates {
+		if a.field != "" {
+			expression(a.field) // elements of relations are joined
+		}
+		fieldsBuff.WriteString(s.aggregateExpression(a) + ` ` + quoteIdentifier(a.alias) + `, `)
+	}
+
+	for _, f := range s.groupBy {
+		expr, _ := expression(f)
+		groupByBuff.WriteString(expr + `, `)
+	}
+
+	if fieldsBuff.Len() > 0 {
+		fieldsStr = fieldsBuff.String()[:fieldsBuff.Len()-2] // trim ", "
+	}
+	if s.distinct {
+		fieldsStr = "DISTINCT " + fieldsStr
+	}
+
+	if groupByBuff.Len() > 0 {
+		groupByStr = "GROUP BY " + groupByBuff.String()[:groupByBuff.Len()-2] // trim ", "
+	}
+
+	return fieldsStr, groupByStr, lateralBuff.String()
+}
+
+// aggregateExpression returns the SQL expression of aggregate a i.e COUNT(*)
+// inner fields are summed and averaged as numbers
+func (s Select) aggregateExpression(a aggregate) string {
+	expr := "*"
+	if a.field != "" {
+		expr, _, _ = getAggregateField(s.GetDialect(), s.GetTable(), s.GetLang(), s.fallback, a.field)
+		if (a.function == AggSum || a.function == AggAvg) && strings.Contains(a.field, ".") {
+			expr = s.GetDialect().Cast(`(`+expr+`)`, "NUMERIC")
+		}
+	}
+	if a.distinct {
+		expr = "DISTINCT " + expr
+	}
+
+	return a.function + `(` + expr + `)`
+}
+
+// aggregateAlias returns the column name of field within an aggregated Select
+func (s Select) aggregateAlias(field string) (string, bool) {
+	if !s.isAggregated() {
+		return "", false
+	}
+
+	for _, a := range s.aggregates {
+		if a.alias == field {
+			return a.alias, true
+		}
+	}
+
+	if innerField, ok := GetInnerField(FieldRelations, field); ok && s.GetTable().IsJSONB(FieldRelations) {
+		return innerField, true
+	}
+
+	return "", false
+}
+
+// getAggregateField returns the SQL expression and alias of field within an aggregated Select
+// relations inner fields also return the join of their elements
+func getAggregateField(d Dialect, table Table, lang string, fallback []string, field string) (string, string, string) {
+	if table.IsMeta(field) || table.IsJSONB(field) {
+		return getFallbackColumn(d, field, lang, fallback), "", ""
+	}
+
+	parent, innerField, ok := table.GetInnerField(field)
+	if !ok {
+		return quoteIdentifier(field), "", ""
+	}
+
+	if IsFieldRelations(parent) {
+		join, element := d.JSONArrayElements(getFallbackAccessor(d, parent, lang, fallback, innerField, false), innerField)
+		return element, innerField, join
+	}
+
+	return getFallbackAccessor(d, parent, lang, fallback, innerField, true), innerField, ""
+}
+
+// orderExpression returns the SQL expression rows are ordered by for field
+func (s Select) orderExpression(field string) string {
+	var (
+		lang  = s.GetLang()
+		table = s.GetTable()
+	)
+
+	if alias, ok := s.aggregateAlias(field); ok {
+		return quoteIdentifier(alias)
+	} else if table.IsMeta(field) || table.IsJSONB(field) {
+		return GetLangField(field, lang)
+	} else if parent, innerField, ok := table.GetInnerField(field); ok {
+		return getFallbackAccessor(s.GetDialect(), parent, lang, s.fallback, innerField, true)
+	} else if len(table.JSONBFields) > 0 { // defaults to inner field of first JSONB field
+		return getFallbackAccessor(s.GetDialect(), table.JSONBFields[0], lang, s.fallback, field, true)
+	}
+
+	return quoteIdentifier(field)
+}
+
+// One returns the first Document matching Select
+// ErrNotFound is returned if there is none
+func (s Select) One() (Document, error) {
+	return s.OneContext(context.Background())
+}
+
+// OneContext returns the first Document matching Select
+// ErrNotFound is returned if there is none
+func (s Select) OneContext(ctx context.Context) (Document, error) {
+	var (
+		doc   Document
+		found bool
+	)
+
+	s.limit = 1
+	s.ToSQL()
+
+	err := s.EachContext(ctx, func(d Document) error {
+		doc, found = d, true
+		return nil
+	})
+	if err != nil {
+		return doc, err
+	}
+
+	if !found {
+		return doc, ErrNotFound
+	}
+
+	return doc, nil
+}
+
+// All returns all Documents matching Select
+func (s Select) All() ([]Document, error) {
+	return s.AllContext(context.Background())
+}
+
+// AllContext returns all Documents matching Select
+func (s Select) AllContext(ctx context.Context) ([]Document, error) {
+	docs := make([]Document, 0)
+
+	err := s.EachContext(ctx, func(d Document) error {
+		docs = append(docs, d)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return docs, nil
+}
+
+// Each calls fn for every Document matching Select
+// Iteration stops at the first error returned by fn
+func (s Select) Each(fn func(Document) error) error {
+	return s.EachContext(context.Background(), fn)
+}
+
+// EachContext calls fn for every Document matching Select
+// Iteration stops at the first error returned by fn
+func (s Select) EachContext(ctx context.Context, fn func(Document) error) error {
+	rows, err := s.RowsContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	return scanDocuments(rows, s.GetLang(), fn)
 }
 
 // SetInner implements Accessor
@@ -214,9 +678,26 @@ func (s Select) Rows() (*sql.Rows, error) {
 		s.ToSQL()
 	}
 
+	if s.err != nil {
+		return nil, s.err
+	}
+
 	return rows(s.GetSQL(), s.GetValues(), s.GetDB())
 }
 
+// RowsContext implements Accessor
+func (s Select) RowsContext(ctx context.Context) (*sql.Rows, error) {
+	if s.GetSQL() == "" || len(s.GetValues()) == 0 {
+		s.ToSQL()
+	}
+
+	if s.err != nil {
+		return nil, s.err
+	}
+
+	return rowsContext(ctx, s.GetSQL(), s.GetValues(), s.GetDB())
+}
+
 // Fields sets the fields for Select
 func (s *Select) Fields(fields ...string) *Select {
 	if len(fields) == 0 {
@@ -226,6 +707,12 @@ func (s *Select) Fields(fields ...string) *Select {
 	return s
 }
 
+// From sets the table for Select
+func (s *Select) From(t Table) *Select {
+	s.SetTable(t)
+	return s
+}
+
 // Where adds a condition clause to the Query
 func (s *Select) Where(c Condition) *Select {
 	s.conditions = append(s.conditions, c)
@@ -238,9 +725,102 @@ func (s *Select) Offset(offset int) *Select {
 	return s
 }
 
+// As names the table of Select alias, for sub-queries to refer to its fields with Ref
+// i.e NewSelect("en").As("author") yields: SELECT ... FROM repo AS "author"
+func (s *Select) As(alias string) *Select {
+	s.alias = alias
+	return s
+}
+
 // Limit sets the Limit for Select
 func (s *Select) Limit(limit int) *Select {
 	s.limit = limit
+	s.hasLimit = true
+	return s
+}
+
+// Fallback sets the langs data is read from when empty in the lang of Select, in order
+// i.e NewSelect("fr").Fallback("en") reads data_en for documents not translated in french
+// Each document is read from a single lang, returned as data_lang: inner fields missing from a partial translation are not read from the fallback langs
+func (s *Select) Fallback(langs ...string) *Select {
+	s.fallback = langs
+	return s
+}
+
+// Langs projects data fields in each of langs (all langs of the table if none), keyed by language
+// i.e Fields("id", "data.title").Langs("en", "fr") yields the title of each document in english and french
+func (s *Select) Langs(langs ...string) *Select {
+	s.langs = make([]string, 0, len(langs))
+	s.langs = append(s.langs, langs...)
+	return s
+}
+
+// OrderRank orders rows by their relevance to the full-text search of query within fields, most relevant first
+// Keyset pagination (After) does not support ordering by rank
+func (s *Select) OrderRank(query string, fields ...string) *Select {
+	search := AndSearch(s.GetLang(), query, fields...)
+	s.order = append(s.order, order{
+		order:  false,
+		search: &search,
+	})
+	return s
+}
+
+// Headline adds a snippet of field highlighting the matches of the full-text search of query to the projection, named alias
+func (s *Select) Headline(field, query, alias string) *Select {
+	s.headlines = append(s.headlines, headline{
+		field: field,
+		query: query,
+		alias: alias,
+	})
+	return s
+}
+
+// Aggregate adds the aggregate function of field to the projection, named alias
+// An empty field aggregates rows i.e COUNT(*)
+func (s *Select) Aggregate(function, field, alias string) *Select {
+	s.aggregates = append(s.aggregates, aggregate{
+		function: function,
+		field:    field,
+		alias:    alias,
+	})
+	return s
+}
+
+// Count adds the count of rows to the projection, named alias
+func (s *Select) Count(alias string) *Select {
+	return s.Aggregate(AggCount, "", alias)
+}
+
+// CountDistinct adds the count of distinct values of field to the projection, named alias
+func (s *Select) CountDistinct(field, alias string) *Select {
+	s.aggregates = append(s.aggregates, aggregate{
+		function: AggCount,
+		field:    field,
+		alias:    alias,
+		distinct: true,
+	})
+	return s
+}
+
+// GroupBy sets the fields rows are grouped by
+// Grouped fields are projected unless Fields are set
+func (s *Select) GroupBy(fields ...string) *Select {
+	s.groupBy = fields
+	return s
+}
+
+// Having adds a condition on grouped rows, aggregate functions are permitted as field function
+// i.e And("en", "id", ">", 5, "COUNT") yields: HAVING COUNT("id") > $1
+// the alias of an aggregate is rendered as the aggregate i.e Count("n") and And("en", "n", ">", 5) yields: HAVING COUNT(*) > $1
+func (s *Select) Having(c Condition) *Select {
+	s.having = append(s.having, c)
+	return s
+}
+
+// Distinct removes duplicate rows from the results
+func (s *Select) Distinct() *Select {
+	s.distinct = true
 	return s
 }
 
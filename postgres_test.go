@@ -1,10 +1,12 @@
 package somesql_test
 
 import (
+	"errors"
 	"fmt"
 	"testing"
 	"time"
 
+	"github.com/lib/pq"
 	uuid "github.com/satori/go.uuid"
 	"github.com/stretchr/testify/assert"
 	"go.lsl.digital/lardwaz/somesql"
@@ -20,6 +22,15 @@ func TestQuery_AsSQL_Select(t *testing.T) {
 		expectedValues []interface{}
 	}
 
+	doc := somesql.Document{
+		ID:        "uuid",
+		CreatedAt: time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC),
+		Data:      map[string]interface{}{"title": "abc", "views": float64(42)},
+	}
+	cursorCreatedAt, _ := somesql.NewSelect("en").Order("created_at", false).CursorFor(doc)
+	cursorViews, _ := somesql.NewSelect("en").Order("data.views", true).Order("title", false).CursorFor(doc)
+	cursorRank, _ := somesql.NewSelect("en").Order("data.rank", true).CursorFor(doc)
+
 	tests := []testCase{
 		// Select ALL
 		{
@@ -202,6 +213,165 @@ func TestQuery_AsSQL_Select(t *testing.T) {
 			checkValues:    true,
 			expectedValues: []interface{}{"uuid"},
 		},
+		// SELECT nested fields
+		{
+			name:           "SELECT nested data fields",
+			query:          somesql.NewSelect("en").Fields("id", "data.seo.title", "data.body", "data.seo.image.caption").Where(somesql.And("en", "data.seo.title", "=", "abc")).Order("data.seo.title", true),
+			expectedSQL:    `SELECT "id", json_build_object('seo', json_build_object('title', "data_en"#>'{seo,title}', 'image', json_build_object('caption', "data_en"#>'{seo,image,caption}')), 'body', "data_en"->'body') "data" FROM repo WHERE "data_en"#>>'{seo,title}' = $1 ORDER BY "data_en"#>>'{seo,title}' ASC LIMIT 10`,
+			checkValues:    true,
+			expectedValues: []interface{}{"abc"},
+		},
+		{
+			name:        "SELECT nested data fields within parent",
+			query:       somesql.NewSelect("fr").Fields("data.seo.title", "data.seo"),
+			expectedSQL: `SELECT json_build_object('seo', "data_fr"->'seo') "data" FROM repo LIMIT 10`,
+		},
+		// SELECT other tables
+		{
+			name:        "SELECT * FROM cards",
+			query:       somesql.NewSelect("en").From(somesql.TableCards),
+			expectedSQL: `SELECT "id", "status", "deck_machine_name", "position", "entity", "entity_type" FROM cards LIMIT 10`,
+		},
+		{
+			name:           "SELECT cards with condition ORDER",
+			query:          somesql.NewSelect("en").From(somesql.TableCards).Fields("id", "position").Where(somesql.And("en", "deck_machine_name", "=", "home")).Order("position", true),
+			expectedSQL:    `SELECT "id", "position" FROM cards WHERE "deck_machine_name" = $1 ORDER BY position ASC LIMIT 10`,
+			checkValues:    true,
+			expectedValues: []interface{}{"home"},
+		},
+		{
+			name:        "SELECT * FROM archives",
+			query:       somesql.NewSelect("fr").From(somesql.TableArchives),
+			expectedSQL: `SELECT "id", "repo_id", "archived_at", "archive" FROM archives LIMIT 10`,
+		},
+		{
+			name:           "SELECT archives inner fields",
+			query:          somesql.NewSelect("en").From(somesql.TableArchives).Fields("repo_id", "archive.title").Where(somesql.And("en", "archive.title", "=", "abc")).Order("archive.title", false),
+			expectedSQL:    `SELECT "repo_id", json_build_object('title', "archive"->'title') "archive" FROM archives WHERE "archive"->>'title' = $1 ORDER BY "archive"->>'title' DESC LIMIT 10`,
+			checkValues:    true,
+			expectedValues: []interface{}{"abc"},
+		},
+		{
+			name:        "SELECT custom table",
+			query:       somesql.NewSelect("en").From(somesql.NewTable("pages", []string{"id"}, "data")).Fields("id", "data.title"),
+			expectedSQL: `SELECT "id", json_build_object('title', "data_en"->'title') "data" FROM pages LIMIT 10`,
+		},
+		// SELECT keyset pagination
+		{
+			name:        "SELECT first page",
+			query:       somesql.NewSelect("en").Fields("id", "data.title").Order("created_at", false).After(""),
+			expectedSQL: `SELECT "id", json_build_object('title', "data_en"->'title') "data" FROM repo ORDER BY created_at DESC NULLS FIRST, id DESC NULLS FIRST LIMIT 10`,
+		},
+		{
+			name:           "SELECT after cursor",
+			query:          somesql.NewSelect("en").Where(somesql.And("en", "type", "=", "article")).Where(somesql.Or("en", "type", "=", "page")).Order("created_at", false).After(cursorCreatedAt),
+			expectedSQL:    `SELECT "id", "created_at", "updated_at", "owner_id", "type", "data_en" FROM repo WHERE ("type" = $1 OR "type" = $2) AND (created_at, id) < ($3, $4) ORDER BY created_at DESC NULLS FIRST, id DESC NULLS FIRST LIMIT 10`,
+			checkValues:    true,
+			expectedValues: []interface{}{"article", "page", "2020-01-02T03:04:05Z", "uuid"},
+		},
+		{
+			name:           "SELECT after cursor mixed order",
+			query:          somesql.NewSelect("en").Order("data.views", true).Order("title", false).After(cursorViews),
+			expectedSQL:    `SELECT "id", "created_at", "updated_at", "owner_id", "type", "data_en" FROM repo WHERE ("data_en"->>'views' > $1 OR ("data_en"->>'views' = $2 AND "data_en"->>'title' < $3) OR ("data_en"->>'views' = $4 AND "data_en"->>'title' = $5 AND id < $6)) ORDER BY "data_en"->>'views' ASC NULLS FIRST, "data_en"->>'title' DESC NULLS FIRST, id DESC NULLS FIRST LIMIT 10`,
+			checkValues:    true,
+			expectedValues: []interface{}{"42", "42", "abc", "42", "abc", "uuid"},
+		},
+		{
+			name:           "SELECT after cursor of a missing inner field",
+			query:          somesql.NewSelect("en").Fields("id").Order("data.rank", true).After(cursorRank),
+			expectedSQL:    `SELECT "id" FROM repo WHERE ("data_en"->>'rank' IS NOT NULL OR ("data_en"->>'rank' IS NULL AND id > $1)) ORDER BY "data_en"->>'rank' ASC NULLS FIRST, id ASC NULLS FIRST LIMIT 10`,
+			checkValues:    true,
+			expectedValues: []interface{}{"uuid"},
+		},
+		// SELECT included relations
+		{
+			name:           "SELECT include relations",
+			query:          somesql.NewSelect("fr").Fields("id", "data.title").Include("relations.author", "id", "data.name").Where(somesql.And("fr", "type", "=", "article")),
+			expectedSQL:    `SELECT "id", json_build_object('title', "data_fr"->'title') "data", COALESCE("include_author"."documents", '[]'::JSON) "relations.author" FROM repo LEFT JOIN LATERAL (SELECT json_agg(json_build_object('id', "rel"."id", 'data', json_build_object('name', "rel"."data_fr"->'name')) ORDER BY "elem"."position") "documents" FROM jsonb_array_elements_text(repo."data_fr"->'author') WITH ORDINALITY "elem"("id", "position") INNER JOIN repo "rel" ON "rel"."id" = "elem"."id"::UUID) "include_author" ON TRUE WHERE "type" = $1 LIMIT 10`,
+			checkValues:    true,
+			expectedValues: []interface{}{"article"},
+		},
+		{
+			name:        "SELECT include relations with fallback",
+			query:       somesql.NewSelect("fr").Fallback("en").Fields("id").Include("relations.author", "id", "data.name"),
+			expectedSQL: `SELECT "id", CASE WHEN NULLIF("data_fr", '{}'::JSONB) IS NOT NULL THEN 'fr' ELSE 'en' END "data_lang", COALESCE("include_author"."documents", '[]'::JSON) "relations.author" FROM repo LEFT JOIN LATERAL (SELECT json_agg(json_build_object('id', "rel"."id", 'data', json_build_object('name', COALESCE(NULLIF("rel"."data_fr", '{}'::JSONB), "rel"."data_en")->'name')) ORDER BY "elem"."position") "documents" FROM jsonb_array_elements_text(COALESCE(NULLIF(repo."data_fr", '{}'::JSONB), repo."data_en")->'author') WITH ORDINALITY "elem"("id", "position") INNER JOIN repo "rel" ON "rel"."id" = "elem"."id"::UUID) "include_author" ON TRUE LIMIT 10`,
+		},
+		{
+			name:        "SELECT include relations all fields",
+			query:       somesql.NewSelect("en").Fields("id").Include("relations.category").Include("relations.author", "data"),
+			expectedSQL: `SELECT "id", COALESCE("include_category"."documents", '[]'::JSON) "relations.category", COALESCE("include_author"."documents", '[]'::JSON) "relations.author" FROM repo LEFT JOIN LATERAL (SELECT json_agg(json_build_object('id', "rel"."id", 'created_at', "rel"."created_at", 'updated_at', "rel"."updated_at", 'owner_id', "rel"."owner_id", 'type', "rel"."type", 'data', "rel"."data_en") ORDER BY "elem"."position") "documents" FROM jsonb_array_elements_text(repo."data_en"->'category') WITH ORDINALITY "elem"("id", "position") INNER JOIN repo "rel" ON "rel"."id" = "elem"."id"::UUID) "include_category" ON TRUE LEFT JOIN LATERAL (SELECT json_agg(json_build_object('data', "rel"."data_en") ORDER BY "elem"."position") "documents" FROM jsonb_array_elements_text(repo."data_en"->'author') WITH ORDINALITY "elem"("id", "position") INNER JOIN repo "rel" ON "rel"."id" = "elem"."id"::UUID) "include_author" ON TRUE LIMIT 10`,
+		},
+		// SELECT full-text search
+		{
+			name:           "SELECT search ORDER BY rank with headline",
+			query:          somesql.NewSelect("fr").Fields("id", "data.title").Headline("data.body", "foo", "snippet").Where(somesql.And("fr", "type", "=", "article")).Where(somesql.AndSearch("fr", "foo", "data.title", "data.body")).OrderRank("foo", "data.title").Order("created_at", false),
+			expectedSQL:    `SELECT "id", json_build_object('title', "data_fr"->'title') "data", ts_headline('french', "data_fr"->>'body', websearch_to_tsquery('french', $1)) "snippet" FROM repo WHERE "type" = $2 AND to_tsvector('french', COALESCE("data_fr"->>'title', '') || ' ' || COALESCE("data_fr"->>'body', '')) @@ websearch_to_tsquery('french', $3) ORDER BY ts_rank(to_tsvector('french', COALESCE("data_fr"->>'title', '')), websearch_to_tsquery('french', $4)) DESC, created_at DESC LIMIT 10`,
+			checkValues:    true,
+			expectedValues: []interface{}{"foo", "article", "foo", "foo"},
+		},
+		// SELECT language fallback
+		{
+			name:           "SELECT * fallback",
+			query:          somesql.NewSelect("fr").Fallback("en").Where(somesql.And("fr", "data.title", "=", "abc")).Where(somesql.AndGroup(somesql.Or("fr", "relations.author", "=", "uuid"))).Order("data.title", true),
+			expectedSQL:    `SELECT "id", "created_at", "updated_at", "owner_id", "type", COALESCE(NULLIF("data_fr", '{}'::JSONB), "data_en") "data_fr", CASE WHEN NULLIF("data_fr", '{}'::JSONB) IS NOT NULL THEN 'fr' ELSE 'en' END "data_lang" FROM repo WHERE COALESCE(NULLIF("data_fr", '{}'::JSONB), "data_en")->>'title' = $1 AND ((jsonb_path_exists(COALESCE(NULLIF("data_fr", '{}'::JSONB), "data_en"), '$.author[*] ? (@ == $val)', json_object(ARRAY['val', $2])::jsonb))) ORDER BY COALESCE(NULLIF("data_fr", '{}'::JSONB), "data_en")->>'title' ASC LIMIT 10`,
+			checkValues:    true,
+			expectedValues: []interface{}{"abc", "uuid"},
+		},
+		{
+			name:        "SELECT inner fields fallback chain",
+			query:       somesql.NewSelect("mu").Fields("id", "data.title", "data.seo.title").Fallback("fr", "en", "mu").Order("body", false),
+			expectedSQL: `SELECT "id", json_build_object('title', COALESCE(NULLIF("data_mu", '{}'::JSONB), NULLIF("data_fr", '{}'::JSONB), "data_en")->'title', 'seo', json_build_object('title', COALESCE(NULLIF("data_mu", '{}'::JSONB), NULLIF("data_fr", '{}'::JSONB), "data_en")#>'{seo,title}')) "data", CASE WHEN NULLIF("data_mu", '{}'::JSONB) IS NOT NULL THEN 'mu' WHEN NULLIF("data_fr", '{}'::JSONB) IS NOT NULL THEN 'fr' ELSE 'en' END "data_lang" FROM repo ORDER BY COALESCE(NULLIF("data_mu", '{}'::JSONB), NULLIF("data_fr", '{}'::JSONB), "data_en")->>'body' DESC LIMIT 10`,
+		},
+		// SELECT multiple languages
+		{
+			name:        "SELECT all langs",
+			query:       somesql.NewSelect("en").Langs(),
+			expectedSQL: `SELECT "id", "created_at", "updated_at", "owner_id", "type", "data_en" "translations.en", "data_fr" "translations.fr" FROM repo LIMIT 10`,
+		},
+		{
+			name:           "SELECT inner fields per lang",
+			query:          somesql.NewSelect("fr").Fields("id", "data.title", "data.seo.title", "relations.author").Langs("fr", "en", "mu").Where(somesql.And("fr", "data.title", "<>", "")),
+			expectedSQL:    `SELECT "id", json_build_object('title', "data_fr"->'title', 'seo', json_build_object('title', "data_fr"#>'{seo,title}'), 'author', "data_fr"->'author') "translations.fr", json_build_object('title', "data_en"->'title', 'seo', json_build_object('title', "data_en"#>'{seo,title}'), 'author', "data_en"->'author') "translations.en", json_build_object('title', "data_mu"->'title', 'seo', json_build_object('title', "data_mu"#>'{seo,title}'), 'author', "data_mu"->'author') "translations.mu" FROM repo WHERE "data_fr"->>'title' <> $1 LIMIT 10`,
+			checkValues:    true,
+			expectedValues: []interface{}{""},
+		},
+		// SELECT aggregates
+		{
+			name:        "SELECT COUNT(*)",
+			query:       somesql.NewSelect("en").Count("count").Limit(0),
+			expectedSQL: `SELECT COUNT(*) "count" FROM repo`,
+		},
+		{
+			name:           "SELECT COUNT(*) GROUP BY type",
+			query:          somesql.NewSelect("en").GroupBy("type").Count("count").Where(somesql.And("en", "owner_id", "=", "uuid")).Order("count", false),
+			expectedSQL:    `SELECT "type", COUNT(*) "count" FROM repo WHERE "owner_id" = $1 GROUP BY "type" ORDER BY "count" DESC LIMIT 10`,
+			checkValues:    true,
+			expectedValues: []interface{}{"uuid"},
+		},
+		{
+			name:        "SELECT DISTINCT data field",
+			query:       somesql.NewSelect("fr").Fields("data.status").Distinct().Order("data.status", true),
+			expectedSQL: `SELECT DISTINCT "data_fr"->>'status' "status" FROM repo ORDER BY "data_fr"->>'status' ASC LIMIT 10`,
+		},
+		{
+			name:           "SELECT COUNT(*) GROUP BY relations HAVING",
+			query:          somesql.NewSelect("en").GroupBy("relations.category").Count("articles").Where(somesql.And("en", "type", "=", "article")).Having(somesql.And("en", "id", ">", 5, "COUNT")).Order("articles", false).Order("relations.category", true),
+			expectedSQL:    `SELECT "category", COUNT(*) "articles" FROM repo CROSS JOIN LATERAL jsonb_array_elements_text("data_en"->'category') "category" WHERE "type" = $1 GROUP BY "category" HAVING COUNT("id") > $2 ORDER BY "articles" DESC, "category" ASC LIMIT 10`,
+			checkValues:    true,
+			expectedValues: []interface{}{"article", 5},
+		},
+		{
+			name:           "SELECT HAVING on aggregate aliases",
+			query:          somesql.NewSelect("en").GroupBy("type").Count("n").Aggregate(somesql.AggSum, "data.views", "views").Having(somesql.And("en", "n", ">", 1)).Having(somesql.AndIn("en", "views", []int{10, 20})).Limit(0),
+			expectedSQL:    `SELECT "type", COUNT(*) "n", SUM(("data_en"->>'views')::NUMERIC) "views" FROM repo GROUP BY "type" HAVING COUNT(*) > $1 AND SUM(("data_en"->>'views')::NUMERIC) = ANY($2)`,
+			checkValues:    true,
+			expectedValues: []interface{}{1, pq.Array([]interface{}{10, 20})},
+		},
+		{
+			name:        "SELECT aggregates of data fields",
+			query:       somesql.NewSelect("en").GroupBy("data.seo.title").Aggregate(somesql.AggSum, "data.views", "views").CountDistinct("owner_id", "owners").Limit(0),
+			expectedSQL: `SELECT "data_en"#>>'{seo,title}' "seo.title", SUM(("data_en"->>'views')::NUMERIC) "views", COUNT(DISTINCT "owner_id") "owners" FROM repo GROUP BY "data_en"#>>'{seo,title}'`,
+		},
 	}
 
 	for i, tt := range tests {
@@ -264,6 +434,82 @@ func TestQuery_AsSQL_Insert(t *testing.T) {
 			expectedSQL:    `INSERT INTO repo ("type", "data_en") VALUES ($1, $2)`,
 			expectedValues: []interface{}{"entityA", `{"tags":["a"]}`},
 		},
+		{
+			name:           "INSERT nested data fields",
+			query:          somesql.NewInsert("en").Fields(somesql.NewFields().Set("data.seo.title", "abc").Set("data.seo.image.caption", "def").Set("data.body", "ghi")),
+			expectedSQL:    `INSERT INTO repo ("data_en") VALUES ($1)`,
+			expectedValues: []interface{}{`{"body":"ghi","seo":{"image":{"caption":"def"},"title":"abc"}}`},
+		},
+		// Insert batch
+		{
+			name:           "INSERT batch",
+			query:          somesql.NewInsert("en").Batch(somesql.NewFields().ID("1").Type("entityA"), somesql.NewFields().ID("2").Type("entityB")),
+			expectedSQL:    `INSERT INTO repo ("id", "type") VALUES ($1, $2), ($3, $4)`,
+			expectedValues: []interface{}{"1", "entityA", "2", "entityB"},
+		},
+		{
+			name:           "INSERT batch (missing fields)",
+			query:          somesql.NewInsert("en").Fields(somesql.NewFields().ID("1").Set("data.body", "abc")).Batch(somesql.NewFields().ID("2").Type("entityB")),
+			expectedSQL:    `INSERT INTO repo ("id", "type", "data_en") VALUES ($1, DEFAULT, $2), ($3, $4, DEFAULT)`,
+			expectedValues: []interface{}{"1", `{"body":"abc"}`, "2", "entityB"},
+		},
+		// Insert on conflict
+		{
+			name:           "INSERT ON CONFLICT DO NOTHING",
+			query:          somesql.NewInsert("en").Fields(somesql.NewFields().ID("1").Type("entityA")).OnConflict(somesql.ConflictDoNothing),
+			expectedSQL:    `INSERT INTO repo ("id", "type") VALUES ($1, $2) ON CONFLICT ("id") DO NOTHING`,
+			expectedValues: []interface{}{"1", "entityA"},
+		},
+		{
+			name:           "INSERT ON CONFLICT overwrite",
+			query:          somesql.NewInsert("en").Fields(somesql.NewFields().ID("1").Type("entityA").Set("data.body", "abc")).OnConflict(somesql.ConflictOverwrite),
+			expectedSQL:    `INSERT INTO repo ("id", "type", "data_en") VALUES ($1, $2, $3) ON CONFLICT ("id") DO UPDATE SET "type" = EXCLUDED."type", "data_en" = EXCLUDED."data_en"`,
+			expectedValues: []interface{}{"1", "entityA", `{"body":"abc"}`},
+		},
+		{
+			name:           "INSERT ON CONFLICT merge (LangFR)",
+			query:          somesql.NewInsert("fr").Fields(somesql.NewFields().ID("1").Type("entityA").Set("data.body", "abc")).OnConflict(somesql.ConflictMerge),
+			expectedSQL:    `INSERT INTO repo ("id", "type", "data_fr") VALUES ($1, $2, $3) ON CONFLICT ("id") DO UPDATE SET "type" = EXCLUDED."type", "data_fr" = repo."data_fr" || EXCLUDED."data_fr"`,
+			expectedValues: []interface{}{"1", "entityA", `{"body":"abc"}`},
+		},
+		{
+			name:           "INSERT ON CONFLICT other field",
+			query:          somesql.NewInsert("en").Into(somesql.TableSlugs).Fields(somesql.NewFields().Set("repo_id", "1").Set("path", "/a")).OnConflict(somesql.ConflictOverwrite, "path"),
+			expectedSQL:    `INSERT INTO slugs ("repo_id", "path") VALUES ($1, $2) ON CONFLICT ("path") DO UPDATE SET "repo_id" = EXCLUDED."repo_id"`,
+			expectedValues: []interface{}{"1", "/a"},
+		},
+		{
+			name:           "INSERT ON CONFLICT nothing to update",
+			query:          somesql.NewInsert("en").Fields(somesql.NewFields().ID("1")).OnConflict(somesql.ConflictMerge),
+			expectedSQL:    `INSERT INTO repo ("id") VALUES ($1) ON CONFLICT ("id") DO NOTHING`,
+			expectedValues: []interface{}{"1"},
+		},
+		// Insert returning
+		{
+			name:           "INSERT RETURNING",
+			query:          somesql.NewInsert("en").Fields(somesql.NewFields().Type("entityA")).Returning("id", "created_at", "data.title"),
+			expectedSQL:    `INSERT INTO repo ("type") VALUES ($1) RETURNING "id", "created_at", json_build_object('title', "data_en"->'title') "data"`,
+			expectedValues: []interface{}{"entityA"},
+		},
+		{
+			name:           "INSERT ON CONFLICT RETURNING",
+			query:          somesql.NewInsert("en").Fields(somesql.NewFields().ID("1")).OnConflict(somesql.ConflictDoNothing).Returning("id"),
+			expectedSQL:    `INSERT INTO repo ("id") VALUES ($1) ON CONFLICT ("id") DO NOTHING RETURNING "id"`,
+			expectedValues: []interface{}{"1"},
+		},
+		// Insert other tables
+		{
+			name:           "INSERT INTO slugs",
+			query:          somesql.NewInsert("en").Into(somesql.TableSlugs).Fields(somesql.NewFields().Set("repo_id", "1").Set("path", "/a").Set("lang", "en")),
+			expectedSQL:    `INSERT INTO slugs ("repo_id", "path", "lang") VALUES ($1, $2, $3)`,
+			expectedValues: []interface{}{"1", "/a", "en"},
+		},
+		{
+			name:           "INSERT INTO archives",
+			query:          somesql.NewInsert("en").Into(somesql.TableArchives).Fields(somesql.NewFields().ID("1").Set("repo_id", "2").Set("archive.title", "abc")),
+			expectedSQL:    `INSERT INTO archives ("id", "repo_id", "archive") VALUES ($1, $2, $3)`,
+			expectedValues: []interface{}{"1", "2", `{"title":"abc"}`},
+		},
 	}
 
 	for i, tt := range tests {
@@ -296,52 +542,123 @@ func TestQuery_AsSQL_Update(t *testing.T) {
 		{
 			name:           "UPDATE data fields",
 			query:          somesql.NewUpdate("en").Fields(somesql.NewFields().Set("data.body", "body value").Set("data.author_id", "123")),
-			expectedSQL:    `UPDATE repo SET "data_en" = jsonb_build_object('body', $1::text, 'author_id', $2::text)::JSONB`,
+			expectedSQL:    `UPDATE repo SET "data_en" = "data_en" || jsonb_build_object('body', $1::TEXT, 'author_id', $2::TEXT)`,
 			expectedValues: []interface{}{"body value", "123"},
 		},
 		{
 			name:           "UPDATE data fields (LangFR)",
 			query:          somesql.NewUpdate("fr").Fields(somesql.NewFields().Set("data.body", "body value").Set("data.author_id", "123")),
-			expectedSQL:    `UPDATE repo SET "data_fr" = jsonb_build_object('body', $1::text, 'author_id', $2::text)::JSONB`,
+			expectedSQL:    `UPDATE repo SET "data_fr" = "data_fr" || jsonb_build_object('body', $1::TEXT, 'author_id', $2::TEXT)`,
 			expectedValues: []interface{}{"body value", "123"},
 		},
 		{
 			name:           "UPDATE meta + data fields",
 			query:          somesql.NewUpdate("en").Fields(somesql.NewFields().ID("1").Type("entityA").Set("data.body", "body value").Set("data.author_id", "123")),
-			expectedSQL:    `UPDATE repo SET "id" = $1, "type" = $2, "data_en" = jsonb_build_object('body', $3::text, 'author_id', $4::text)::JSONB`,
+			expectedSQL:    `UPDATE repo SET "id" = $1, "type" = $2, "data_en" = "data_en" || jsonb_build_object('body', $3::TEXT, 'author_id', $4::TEXT)`,
 			expectedValues: []interface{}{"1", "entityA", "body value", "123"},
 		},
 		{
 			name:           "UPDATE meta + data fields (LangFR)",
 			query:          somesql.NewUpdate("fr").Fields(somesql.NewFields().ID("1").Type("entityA").Set("data.body", "body value").Set("data.author_id", "123")),
-			expectedSQL:    `UPDATE repo SET "id" = $1, "type" = $2, "data_fr" = jsonb_build_object('body', $3::text, 'author_id', $4::text)::JSONB`,
+			expectedSQL:    `UPDATE repo SET "id" = $1, "type" = $2, "data_fr" = "data_fr" || jsonb_build_object('body', $3::TEXT, 'author_id', $4::TEXT)`,
 			expectedValues: []interface{}{"1", "entityA", "body value", "123"},
 		},
 		{
 			name:           "UPDATE meta + data fields conditions",
 			query:          somesql.NewUpdate("en").Fields(somesql.NewFields().ID("1").Type("entityA").Set("data.body", "body value").Set("data.author_id", "123")).Where(somesql.And("en", "id", "=", "234")),
-			expectedSQL:    `UPDATE repo SET "id" = $1, "type" = $2, "data_en" = jsonb_build_object('body', $3::text, 'author_id', $4::text)::JSONB WHERE "id" = $5`,
+			expectedSQL:    `UPDATE repo SET "id" = $1, "type" = $2, "data_en" = "data_en" || jsonb_build_object('body', $3::TEXT, 'author_id', $4::TEXT) WHERE "id" = $5`,
 			expectedValues: []interface{}{"1", "entityA", "body value", "123", "234"},
 		},
 		{
 			name:           "UPDATE meta + data fields conditions 2",
 			query:          somesql.NewUpdate("en").Fields(somesql.NewFields().ID("1").Type("entityA").Set("data.body", "body value").Set("data.author_id", "123")).Where(somesql.And("en", "data.author_id", "=", "234")),
-			expectedSQL:    `UPDATE repo SET "id" = $1, "type" = $2, "data_en" = jsonb_build_object('body', $3::text, 'author_id', $4::text)::JSONB WHERE "data_en"->>'author_id' = $5`,
+			expectedSQL:    `UPDATE repo SET "id" = $1, "type" = $2, "data_en" = "data_en" || jsonb_build_object('body', $3::TEXT, 'author_id', $4::TEXT) WHERE "data_en"->>'author_id' = $5`,
 			expectedValues: []interface{}{"1", "entityA", "body value", "123", "234"},
 		},
 		// Update relations
 		{
 			name:           "UPDATE set relation only",
 			query:          somesql.NewUpdate("en").Fields(somesql.NewFields().Set("relations.tags", []string{"a", "b"})),
-			expectedSQL:    `UPDATE repo SET "data_en" = jsonb_build_object('tags', $1::JSONB)::JSONB`,
+			expectedSQL:    `UPDATE repo SET "data_en" = "data_en" || jsonb_build_object('tags', $1::JSONB)`,
 			expectedValues: []interface{}{`["a","b"]`},
 		},
 		{
 			name:           "UPDATE set relation with data",
 			query:          somesql.NewUpdate("en").Fields(somesql.NewFields().Set("data.body", "body value").Set("relations.tags", []string{"a", "b"})),
-			expectedSQL:    `UPDATE repo SET "data_en" = jsonb_build_object('body', $1::text, 'tags', $2::JSONB)::JSONB`,
+			expectedSQL:    `UPDATE repo SET "data_en" = "data_en" || jsonb_build_object('body', $1::TEXT, 'tags', $2::JSONB)`,
 			expectedValues: []interface{}{"body value", `["a","b"]`},
 		},
+		// Update arrays
+		{
+			name:           "UPDATE add to relation",
+			query:          somesql.NewUpdate("en").Fields(somesql.NewFields().Add("relations.author", "a")).Where(somesql.And("en", "id", "=", "1")),
+			expectedSQL:    `UPDATE repo SET "data_en" = jsonb_set("data_en", '{author}', COALESCE("data_en"->'author', '[]'::JSONB) || $1::JSONB) WHERE "id" = $2`,
+			expectedValues: []interface{}{`["a"]`, "1"},
+		},
+		{
+			name:           "UPDATE add unique to relation",
+			query:          somesql.NewUpdate("en").Fields(somesql.NewFields().AddUnique("relations.author", []string{"a", "b"})),
+			expectedSQL:    `UPDATE repo SET "data_en" = jsonb_set("data_en", '{author}', COALESCE("data_en"->'author', '[]'::JSONB) || COALESCE((SELECT jsonb_agg(DISTINCT "elem") FROM jsonb_array_elements($1::JSONB) "elem" WHERE NOT COALESCE("data_en"->'author', '[]'::JSONB) @> jsonb_build_array("elem")), '[]'::JSONB))`,
+			expectedValues: []interface{}{`["a","b"]`},
+		},
+		{
+			name:           "UPDATE remove from data array",
+			query:          somesql.NewUpdate("fr").Fields(somesql.NewFields().Remove("data.tags", "a")),
+			expectedSQL:    `UPDATE repo SET "data_fr" = jsonb_set("data_fr", '{tags}', COALESCE((SELECT jsonb_agg("elem") FROM jsonb_array_elements(COALESCE("data_fr"->'tags', '[]'::JSONB)) "elem" WHERE NOT $1::JSONB @> jsonb_build_array("elem")), '[]'::JSONB))`,
+			expectedValues: []interface{}{`["a"]`},
+		},
+		{
+			name:           "UPDATE set data + add and remove relations",
+			query:          somesql.NewUpdate("en").Fields(somesql.NewFields().Type("entityA").Set("data.body", "abc").Add("relations.author", "a").Remove("relations.tags", []string{"x", "y"})),
+			expectedSQL:    `UPDATE repo SET "type" = $1, "data_en" = jsonb_set(jsonb_set("data_en" || jsonb_build_object('body', $2::TEXT), '{author}', COALESCE("data_en"->'author', '[]'::JSONB) || $3::JSONB), '{tags}', COALESCE((SELECT jsonb_agg("elem") FROM jsonb_array_elements(COALESCE("data_en"->'tags', '[]'::JSONB)) "elem" WHERE NOT $4::JSONB @> jsonb_build_array("elem")), '[]'::JSONB))`,
+			expectedValues: []interface{}{"entityA", "abc", `["a"]`, `["x","y"]`},
+		},
+		// Update nested
+		{
+			name:           "UPDATE nested data fields",
+			query:          somesql.NewUpdate("en").Fields(somesql.NewFields().Set("data.body", "abc").Set("data.seo.title", "def").Set("data.seo.image.caption", "ghi")),
+			expectedSQL:    `UPDATE repo SET "data_en" = jsonb_set(jsonb_set(jsonb_set(jsonb_set("data_en" || jsonb_build_object('body', $1::TEXT), '{seo}', COALESCE("data_en"->'seo', '{}'::JSONB)), '{seo,title}', to_jsonb($2::TEXT)), '{seo,image}', COALESCE("data_en"#>'{seo,image}', '{}'::JSONB)), '{seo,image,caption}', to_jsonb($3::TEXT))`,
+			expectedValues: []interface{}{"abc", "def", "ghi"},
+		},
+		{
+			name:           "UPDATE nested array",
+			query:          somesql.NewUpdate("en").Fields(somesql.NewFields().Add("data.seo.keywords", "a")),
+			expectedSQL:    `UPDATE repo SET "data_en" = jsonb_set(jsonb_set("data_en", '{seo}', COALESCE("data_en"->'seo', '{}'::JSONB)), '{seo,keywords}', COALESCE("data_en"#>'{seo,keywords}', '[]'::JSONB) || $1::JSONB)`,
+			expectedValues: []interface{}{`["a"]`},
+		},
+		{
+			name:           "UPDATE replace nested data fields",
+			query:          somesql.NewUpdate("en").Fields(somesql.NewFields().Set("data.seo.title", "def")).Replace(true),
+			expectedSQL:    `UPDATE repo SET "data_en" = jsonb_set(jsonb_set('{}'::JSONB, '{seo}', '{}'::JSONB), '{seo,title}', to_jsonb($1::TEXT))`,
+			expectedValues: []interface{}{"def"},
+		},
+		// Update replace
+		{
+			name:           "UPDATE replace data fields",
+			query:          somesql.NewUpdate("en").Fields(somesql.NewFields().Set("data.body", "body value").Set("data.author_id", "123")).Replace(true),
+			expectedSQL:    `UPDATE repo SET "data_en" = jsonb_build_object('body', $1::TEXT, 'author_id', $2::TEXT)::JSONB`,
+			expectedValues: []interface{}{"body value", "123"},
+		},
+		{
+			name:           "UPDATE replace meta + data fields conditions (LangFR)",
+			query:          somesql.NewUpdate("fr").Fields(somesql.NewFields().Type("entityA").Set("data.body", "body value").Set("relations.tags", []string{"a", "b"})).Where(somesql.And("fr", "id", "=", "234")).Replace(true),
+			expectedSQL:    `UPDATE repo SET "type" = $1, "data_fr" = jsonb_build_object('body', $2::TEXT, 'tags', $3::JSONB)::JSONB WHERE "id" = $4`,
+			expectedValues: []interface{}{"entityA", "body value", `["a","b"]`, "234"},
+		},
+		// Update returning
+		{
+			name:           "UPDATE RETURNING",
+			query:          somesql.NewUpdate("en").Fields(somesql.NewFields().Type("entityA")).Where(somesql.And("en", "id", "=", "1")).Returning("id", "updated_at", "data"),
+			expectedSQL:    `UPDATE repo SET "type" = $1 WHERE "id" = $2 RETURNING "id", "updated_at", "data_en"`,
+			expectedValues: []interface{}{"entityA", "1"},
+		},
+		// Update other tables
+		{
+			name:           "UPDATE cards",
+			query:          somesql.NewUpdate("en").Table(somesql.TableCards).Fields(somesql.NewFields().Set("status", "published").Set("position", 2)).Where(somesql.And("en", "id", "=", "1")),
+			expectedSQL:    `UPDATE cards SET "status" = $1, "position" = $2 WHERE "id" = $3`,
+			expectedValues: []interface{}{"published", 2, "1"},
+		},
 	}
 
 	for i, tt := range tests {
@@ -399,6 +716,20 @@ func TestQuery_AsSQL_Delete(t *testing.T) {
 			checkValues:    true,
 			expectedValues: []interface{}{"uuid"},
 		},
+		{
+			name:           "DELETE RETURNING",
+			query:          somesql.NewDelete("en").Where(somesql.And("en", "type", "=", "entityA")).Returning("id", "relations.author"),
+			expectedSQL:    `DELETE FROM repo WHERE "type" = $1 RETURNING "id", json_build_object('author', "data_en"->'author') "data"`,
+			checkValues:    true,
+			expectedValues: []interface{}{"entityA"},
+		},
+		{
+			name:           "DELETE FROM cardschedules",
+			query:          somesql.NewDelete("en").From(somesql.TableCardSchedules).Where(somesql.And("en", "card_id", "=", "uuid")),
+			expectedSQL:    `DELETE FROM cardschedules WHERE "card_id" = $1`,
+			checkValues:    true,
+			expectedValues: []interface{}{"uuid"},
+		},
 	}
 
 	for i, tt := range tests {
@@ -413,3 +744,18 @@ func TestQuery_AsSQL_Delete(t *testing.T) {
 		})
 	}
 }
+
+func TestSelect_After_InvalidCursor(t *testing.T) {
+	_, err := somesql.NewSelect("en").Order("created_at", false).After("not a cursor").Rows()
+	assert.Equal(t, somesql.ErrInvalidCursor, err)
+
+	cursor, _ := somesql.NewSelect("en").CursorFor(somesql.Document{ID: "uuid"})
+	_, err = somesql.NewSelect("en").Order("created_at", false).After(cursor).Rows()
+	assert.Equal(t, somesql.ErrInvalidCursor, err)
+
+	_, _, err = somesql.NewSelect("en").Order("created_at", false).After(cursor).Offset(10).Build()
+	assert.True(t, errors.Is(err, somesql.ErrInvalidCursor), "offset of keyset pagination")
+
+	_, _, err = somesql.NewSelect("en").Offset(10).After("").Build()
+	assert.True(t, errors.Is(err, somesql.ErrInvalidCursor), "offset of first page")
+}
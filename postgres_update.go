@@ -1,13 +1,15 @@
 package somesql
 
 import (
+	"context"
 	"database/sql"
 	"encoding/json"
+	"fmt"
 	"strings"
 )
 
-// Update generates Postgres UPDATE statement
-// Implements: Mutator
+// Update generates UPDATE statement of its Dialect (Postgres by default)
+// Implements: Mutator, Returner
 type Update struct {
 	fields     Fields
 	conditions []Condition
@@ -15,6 +17,12 @@ type Update struct {
 	values     []interface{}
 	db         *sql.DB
 	lang       string
+	table      Table
+	dialect    Dialect
+	returning  []string
+	replace    bool
+	alias      string
+	err        error
 }
 
 // NewUpdate returns a new Update
@@ -22,6 +30,8 @@ func NewUpdate(lang string, db ...*sql.DB) *Update {
 	var s Update
 
 	s.lang = lang
+	s.table = TableRepo
+	s.dialect = DefaultDialect
 
 	if len(db) > 0 {
 		s.db = db[0]
@@ -50,6 +60,30 @@ func (s Update) GetLang() string {
 	return s.lang
 }
 
+// SetTable implements Statement
+func (s *Update) SetTable(t Table) {
+	s.table = t
+}
+
+// GetTable implements Statement
+func (s Update) GetTable() Table {
+	return s.table
+}
+
+// SetDialect implements Statement
+func (s *Update) SetDialect(d Dialect) {
+	s.dialect = d
+}
+
+// GetDialect implements Statement
+func (s Update) GetDialect() Dialect {
+	if s.dialect == nil {
+		return DefaultDialect
+	}
+
+	return s.dialect
+}
+
 // GetSQL implements Statement
 func (s Update) GetSQL() string {
 	return s.sql
@@ -65,40 +99,123 @@ func (s *Update) ToSQL() {
 	var (
 		fieldsStr     string
 		conditionsStr string
-		dataFieldLang string
+		table         = s.GetTable()
+		d             = s.GetDialect()
 
 		fieldsBuff     strings.Builder
 		metaFieldsBuff strings.Builder
-		dataFieldsBuff strings.Builder
 
-		metaValues []interface{}
-		dataValues []interface{}
+		metaValues  []interface{}
+		jsonbSets   []string
+		jsonbValues []interface{}
+
+		errs = append(checkLangs(table, s.GetLang()), checkFields(table, s.fields)...)
 	)
 
-	dataFieldLang = GetLangFieldData(s.GetLang())
+	for _, f := range s.returning {
+		errs = appendError(errs, checkField(table, f))
+	}
+	if s.alias != "" {
+		errs = appendError(errs, checkIdentifier(s.alias, s.alias))
+	}
 
-	fields, values := s.fields.List()
+	// jsonValue returns value encoded as JSON, the error is reported on innerField of f
+	jsonValue := func(f, innerField string, value interface{}) interface{} {
+		jsonBytes, err := json.Marshal(value)
+		if err != nil {
+			errs = append(errs, FieldError{Field: f + "." + innerField, Err: fmt.Errorf("%w: %v", ErrInvalidJSON, err)})
+			return nil
+		}
+		return string(jsonBytes)
+	}
+
+	// typedValue returns value sent as a typed parameter, the error is reported on innerField of f
+	typedValue := func(f, innerField string, value interface{}) interface{} {
+		errs = append(errs, checkValues(f+"."+innerField, []interface{}{value})...)
+		return value
+	}
+
+	fields, values := s.fields.ListOf(table)
 
 	// Processing fields and values
 	for i, f := range fields {
-		if IsFieldData(f) {
+		if table.IsJSONB(f) && !IsFieldRelations(f) {
+			var (
+				jsonbSet    string
+				jsonbPairs  []string
+				pathSets    []pathSet
+				pathValues  []interface{}
+				ensured     = make(map[string]bool)
+				column      = GetLangField(f, s.GetLang())
+				columnSQL   = quoteIdentifier(column)
+				emptyObject = d.JSONLiteral("{}")
+			)
+
 			if jsonbFields, ok := values[i].(JSONBFields); ok {
-				innerFields, innerValues, _ := jsonbFields.GetOrderedList()
+				innerFields, innerValues, actions := jsonbFields.GetOrderedList()
 				for idx, innerField := range innerFields {
-					if _, ok := innerValues[idx].([]interface{}); ok {
-						dataFieldsBuff.WriteString(`'` + innerField + `', ?::JSONB, `)
-						if jsonBytes, err := json.Marshal(innerValues[idx]); err == nil {
-							dataValues = append(dataValues, string(jsonBytes))
+					isArrayAction := actions[idx] != NoneJSONBArr && !s.replace
+
+					if isArrayAction || strings.Contains(innerField, ".") {
+						// Missing parents of nested fields are created first
+						for _, parent := range getParentPaths(innerField) {
+							if ensured[parent] {
+								continue
+							}
+							ensured[parent] = true
+
+							parentSQL := emptyObject
+							if !s.replace {
+								parentSQL = `COALESCE(` + d.JSONAccessor(columnSQL, parent, false) + `, ` + emptyObject + `)`
+							}
+							pathSets = append(pathSets, pathSet{innerField: parent, value: parentSQL})
 						}
-					} else {
+					}
 
-						dataFieldsBuff.WriteString(`'` + innerField + `', ?::` + getSQLType(innerValues[idx]) + `, `)
-						dataValues = append(dataValues, innerValues[idx])
+					if isArrayAction {
+						pathSets = append(pathSets, pathSet{innerField: innerField, value: d.JSONArrayAction(d.JSONAccessor(columnSQL, innerField, false), actions[idx])})
+						pathValues = append(pathValues, jsonValue(f, innerField, asSlice(innerValues[idx])))
+					} else if strings.Contains(innerField, ".") {
+						if _, ok := innerValues[idx].([]interface{}); ok {
+							pathSets = append(pathSets, pathSet{innerField: innerField, value: d.JSONParam()})
+							pathValues = append(pathValues, jsonValue(f, innerField, innerValues[idx]))
+						} else {
+							pathSets = append(pathSets, pathSet{innerField: innerField, value: d.JSONValue(getSQLType(innerValues[idx]))})
+							pathValues = append(pathValues, typedValue(f, innerField, innerValues[idx]))
+						}
+					} else if _, ok := innerValues[idx].([]interface{}); ok {
+						jsonbPairs = append(jsonbPairs, quoteLiteral(innerField)+`, `+d.JSONParam())
+						jsonbValues = append(jsonbValues, jsonValue(f, innerField, innerValues[idx]))
+					} else {
+						jsonbPairs = append(jsonbPairs, quoteLiteral(innerField)+`, `+d.TypedParam(getSQLType(innerValues[idx])))
+						jsonbValues = append(jsonbValues, typedValue(f, innerField, innerValues[idx]))
 					}
 				}
 			}
-		} else if IsFieldMeta(f) { // Check if Meta fields
-			metaFieldsBuff.WriteString(`"` + f + `" = ?, `)
+
+			if len(jsonbPairs) > 0 {
+				if s.replace {
+					jsonbSet = d.Cast(d.JSONDocument(jsonbPairs), "JSONB")
+				} else { // only patch the given keys
+					jsonbSet = d.JSONMerge(columnSQL, d.JSONDocument(jsonbPairs))
+				}
+			} else if len(pathSets) > 0 {
+				jsonbSet = columnSQL
+				if s.replace {
+					jsonbSet = emptyObject
+				}
+			}
+
+			for _, p := range pathSets {
+				jsonbSet = d.JSONSet(jsonbSet, p.innerField, p.value)
+			}
+			jsonbValues = append(jsonbValues, pathValues...)
+
+			if jsonbSet != "" {
+				jsonbSets = append(jsonbSets, columnSQL+` = `+jsonbSet)
+			}
+		} else if table.IsMeta(f) { // Check if Meta fields
+			metaFieldsBuff.WriteString(quoteIdentifier(f) + ` = ?, `)
 			metaValues = append(metaValues, values[i])
 		}
 	}
@@ -113,27 +230,43 @@ func (s *Update) ToSQL() {
 		s.values = append(s.values, metaValues...)
 	}
 
-	// Set data fields
-	if dataFieldsBuff.Len() > 0 {
-		dataFieldsStr := dataFieldsBuff.String()[:dataFieldsBuff.Len()-2] // trim ", "
-		fieldsBuff.WriteString(`"` + dataFieldLang + `" = jsonb_build_object(` + dataFieldsStr + `)::JSONB, `)
-		s.values = append(s.values, dataValues...)
+	// Set JSONB fields
+	for _, jsonbSet := range jsonbSets {
+		fieldsBuff.WriteString(jsonbSet + `, `)
 	}
+	s.values = append(s.values, jsonbValues...)
 
-	conditions, condValues := processConditions(s.conditions)
+	errs = append(errs, checkConditions(s.conditions, table)...)
+	conditions, condValues := processConditions(withContext(s.conditions, conditionContext{dialect: d}))
 	if len(conditions) > 0 {
 		conditionsStr = " WHERE " + conditions
 	}
 
 	if fieldsBuff.Len() > 0 {
 		fieldsStr = fieldsBuff.String()[:fieldsBuff.Len()-2] // trim ", "
+	} else {
+		errs = append(errs, fmt.Errorf("%w: SET", ErrEmptyClause))
 	}
 
 	s.values = append(s.values, condValues...)
 
-	sql := "UPDATE " + Table + " SET " + fieldsStr + " " + conditionsStr
+	sql := "UPDATE " + getTableClause(s.table, s.alias) + " SET " + fieldsStr + " " + conditionsStr + " " + processReturning(d, table, s.GetLang(), s.returning)
+
+	if len(errs) == 0 {
+		if err := checkPlaceholders(sql, s.values); err != nil {
+			errs = append(errs, err)
+		}
+	}
 
-	s.sql = cleanStatement(processPlaceholders(sql))
+	s.sql = cleanStatement(d.Placeholders(sql))
+	s.err = newBuildError(errs)
+}
+
+// Build implements Statement
+func (s *Update) Build() (string, []interface{}, error) {
+	s.ToSQL()
+
+	return s.GetSQL(), s.GetValues(), s.err
 }
 
 // Exec implements Mutator
@@ -142,26 +275,177 @@ func (s Update) Exec(autocommit bool) error {
 		s.ToSQL()
 	}
 
+	if s.err != nil {
+		return s.err
+	}
+
 	return exec(s.GetSQL(), s.GetValues(), s.GetDB(), autocommit)
 }
 
+// ExecContext implements Mutator
+func (s Update) ExecContext(ctx context.Context, autocommit bool) error {
+	if s.GetSQL() == "" || len(s.GetValues()) == 0 {
+		s.ToSQL()
+	}
+
+	if s.err != nil {
+		return s.err
+	}
+
+	return execContext(ctx, s.GetSQL(), s.GetValues(), s.GetDB(), autocommit)
+}
+
 // ExecTx implements Mutator
 func (s Update) ExecTx(tx *sql.Tx, autocommit bool) error {
 	if s.GetSQL() == "" || len(s.GetValues()) == 0 {
 		s.ToSQL()
 	}
 
+	if s.err != nil {
+		return s.err
+	}
+
 	return execTx(s.GetSQL(), s.GetValues(), tx, autocommit)
 }
 
+// ExecTxContext implements Mutator
+func (s Update) ExecTxContext(ctx context.Context, tx *sql.Tx, autocommit bool) error {
+	if s.GetSQL() == "" || len(s.GetValues()) == 0 {
+		s.ToSQL()
+	}
+
+	if s.err != nil {
+		return s.err
+	}
+
+	return execTxContext(ctx, s.GetSQL(), s.GetValues(), tx, autocommit)
+}
+
+// Table sets the table for Update
+func (s *Update) Table(t Table) *Update {
+	s.SetTable(t)
+	return s
+}
+
 // Fields sets the fields and values for Update
 func (s *Update) Fields(fields Fields) *Update {
 	s.fields = fields
 	return s
 }
 
+// As names the table of Update alias, for sub-queries to refer to its fields with Ref
+func (s *Update) As(alias string) *Update {
+	s.alias = alias
+	return s
+}
+
+// Replace sets whether JSONB fields are replaced as a whole with the given keys
+// By default, only the given keys are updated and other keys are kept
+func (s *Update) Replace(replace bool) *Update {
+	s.replace = replace
+	return s
+}
+
 // Where adds a condition clause to the Query
 func (s *Update) Where(c Condition) *Update {
 	s.conditions = append(s.conditions, c)
 	return s
 }
+
+// ExecReturning implements Returner
+// Fields of the table are returned unless set with Returning
+func (s Update) ExecReturning(autocommit bool) ([]Document, error) {
+	return s.ExecReturningContext(context.Background(), autocommit)
+}
+
+// ExecReturningContext implements Returner
+// Fields of the table are returned unless set with Returning
+func (s Update) ExecReturningContext(ctx context.Context, autocommit bool) ([]Document, error) {
+	s.useDefaultReturning()
+
+	stmts, err := s.statements()
+	if err != nil {
+		return nil, err
+	}
+
+	return execReturning(ctx, stmts, s.GetDB(), autocommit, s.GetLang())
+}
+
+// ExecTxReturning implements Returner
+// Fields of the table are returned unless set with Returning
+func (s Update) ExecTxReturning(tx *sql.Tx, autocommit bool) ([]Document, error) {
+	return s.ExecTxReturningContext(context.Background(), tx, autocommit)
+}
+
+// ExecTxReturningContext implements Returner
+// Fields of the table are returned unless set with Returning
+func (s Update) ExecTxReturningContext(ctx context.Context, tx *sql.Tx, autocommit bool) ([]Document, error) {
+	s.useDefaultReturning()
+
+	stmts, err := s.statements()
+	if err != nil {
+		return nil, err
+	}
+
+	return execReturningTx(ctx, stmts, tx, autocommit, s.GetLang())
+}
+
+// RowsTx implements Returner
+// Fields of the table are returned unless set with Returning
+func (s Update) RowsTx(tx *sql.Tx) (*sql.Rows, error) {
+	return s.RowsTxContext(context.Background(), tx)
+}
+
+// RowsTxContext implements Returner
+// Fields of the table are returned unless set with Returning
+func (s Update) RowsTxContext(ctx context.Context, tx *sql.Tx) (*sql.Rows, error) {
+	s.useDefaultReturning()
+	s.ToSQL()
+
+	if s.err != nil {
+		return nil, s.err
+	}
+
+	return rowsTx(ctx, s.GetSQL(), s.GetValues(), tx)
+}
+
+// Returning sets the fields returned by Update
+func (s *Update) Returning(fields ...string) *Update {
+	s.returning = fields
+	return s
+}
+
+// useDefaultReturning returns all fields of the table if none were set
+func (s *Update) useDefaultReturning() {
+	if len(s.returning) == 0 {
+		s.returning = s.GetTable().Fields()
+	}
+}
+
+// statements returns the UPDATE statement
+func (s Update) statements() ([]statement, error) {
+	s.ToSQL()
+
+	return []statement{{sql: s.GetSQL(), values: s.GetValues()}}, s.err
+}
+
+// pathSet represents a JSON value set at innerField of a JSONB field
+type pathSet struct {
+	innerField string
+	value      string
+}
+
+// getParentPaths returns the parents of a nested innerField, shallowest first
+// i.e seo.image.caption => [seo, seo.image]
+func getParentPaths(innerField string) []string {
+	var (
+		parents []string
+		keys    = strings.Split(innerField, ".")
+	)
+
+	for i := 1; i < len(keys); i++ {
+		parents = append(parents, strings.Join(keys[:i], "."))
+	}
+
+	return parents
+}
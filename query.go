@@ -1,6 +1,7 @@
 package somesql //import go.lsl.digital/lardwaz/somesql
 
 import (
+	"context"
 	"database/sql"
 )
 
@@ -9,17 +10,22 @@ const (
 	AndCondition uint8 = iota
 	// OrCondition represents a condition added to the query via OR keyword
 	OrCondition
-
-	// Table represents the table name
-	// TODO: make configurable
-	Table = "repo"
 )
 
 const (
 	// None represents a simple way of explicitly specifying no value
 	None = ""
+
+	// MaxPlaceholders represents the maximum number of values in a single statement
+	MaxPlaceholders = 65535
 )
 
+// statement represents a single sql statement along with its values
+type statement struct {
+	sql    string
+	values []interface{}
+}
+
 // Statement represents a composable statement
 // Can be consumed by Mutator or Accessor
 type Statement interface {
@@ -27,16 +33,34 @@ type Statement interface {
 	GetDB() *sql.DB
 	SetLang(lang string)
 	GetLang() string
+	SetTable(t Table)
+	GetTable() Table
+	SetDialect(d Dialect)
+	GetDialect() Dialect
 	GetSQL() string
 	GetValues() []interface{}
 	ToSQL()
+	Build() (string, []interface{}, error)
 }
 
 // Mutator is any statement which modifies values in store
 type Mutator interface {
 	Statement
 	Exec(autocommit bool) error
+	ExecContext(ctx context.Context, autocommit bool) error
 	ExecTx(tx *sql.Tx, autocommit bool) error
+	ExecTxContext(ctx context.Context, tx *sql.Tx, autocommit bool) error
+}
+
+// Returner is any Mutator which can return the rows it modified (RETURNING)
+type Returner interface {
+	Mutator
+	ExecReturning(autocommit bool) ([]Document, error)
+	ExecReturningContext(ctx context.Context, autocommit bool) ([]Document, error)
+	ExecTxReturning(tx *sql.Tx, autocommit bool) ([]Document, error)
+	ExecTxReturningContext(ctx context.Context, tx *sql.Tx, autocommit bool) ([]Document, error)
+	RowsTx(tx *sql.Tx) (*sql.Rows, error)
+	RowsTxContext(ctx context.Context, tx *sql.Tx) (*sql.Rows, error)
 }
 
 // Accessor is any statement which retrieves values from store
@@ -45,6 +69,7 @@ type Accessor interface {
 	SetInner(inner bool)
 	IsInner() bool
 	Rows() (*sql.Rows, error)
+	RowsContext(ctx context.Context) (*sql.Rows, error)
 }
 
 // Condition represents a conditional clause in a statement
@@ -52,3 +77,130 @@ type Condition interface {
 	ConditionType() uint8
 	AsSQL(in ...bool) (string, []interface{})
 }
+
+// conditionContext holds the settings of the statement conditions are rendered within
+// fallback langs data fields are read across and dialect of the SQL (Postgres if none)
+// aggregates are the expressions of the aggregates by alias, set within HAVING only
+type conditionContext struct {
+	fallback   []string
+	dialect    Dialect
+	aggregates map[string]string
+}
+
+// getDialect returns the dialect of the context, Postgres by default
+func (ctx conditionContext) getDialect() Dialect {
+	if ctx.dialect == nil {
+		return Postgres
+	}
+
+	return ctx.dialect
+}
+
+// isHaving returns true if conditions are rendered within HAVING, where aggregate functions are permitted
+func (ctx conditionContext) isHaving() bool {
+	return ctx.aggregates != nil
+}
+
+// contextCondition is implemented by conditions rendered according to the statement
+// withContext returns the condition rendered within ctx
+type contextCondition interface {
+	withContext(ctx conditionContext) Condition
+}
+
+// withContext returns conds rendered within ctx
+func withContext(conds []Condition, ctx conditionContext) []Condition {
+	contextConds := make([]Condition, len(conds))
+	for i, cond := range conds {
+		if c, ok := cond.(contextCondition); ok {
+			cond = c.withContext(ctx)
+		}
+		contextConds[i] = cond
+	}
+
+	return contextConds
+}
+
+// checkedCondition is implemented by conditions which validate their fields and values
+// check returns the errors of the condition within table
+type checkedCondition interface {
+	check(table Table) []error
+}
+
+// checkConditions returns the errors of conds within table
+func checkConditions(conds []Condition, table Table) []error {
+	var errs []error
+
+	for _, cond := range conds {
+		if c, ok := cond.(checkedCondition); ok {
+			errs = append(errs, c.check(table)...)
+		}
+	}
+
+	return errs
+}
+
+// Dialect renders the SQL specific to a DBMS
+// innerField arguments are dot-separated paths within JSON documents i.e seo.title
+type Dialect interface {
+	// Placeholders replaces ? with the placeholders of the dialect and £ with a literal ?
+	Placeholders(sql string) string
+	// Placeholder returns the placeholder of the i-th value (from 1)
+	Placeholder(i int) string
+	// ArrayParam returns the SQL of a list of values bound as an array, and the values to send
+	ArrayParam(values []interface{}) (string, []interface{})
+	// In returns the condition of lhs being within list (see ArrayParam), or not
+	In(lhs, list string, not bool) string
+	// Default returns the value of a column set to its default, empty if there is none
+	// rows of an Insert are then executed as one statement per set of columns
+	Default() string
+	// Cast returns expr as sqlType (INT, TEXT, BOOLEAN, NUMERIC or JSONB)
+	Cast(expr, sqlType string) string
+	// Compare returns the condition lhs operator rhs of a comparison, pattern matching or BETWEEN operator
+	Compare(lhs, operator, rhs string) string
+
+	// JSONAccessor returns the SQL to access innerField of JSON expr, as JSON or as text
+	JSONAccessor(expr, innerField string, asText bool) string
+	// JSONLiteral returns the SQL of JSON document json i.e {} or []
+	JSONLiteral(json string) string
+	// JSONObject returns the SQL of a JSON object of pairs of quoted keys and SQL values, as projected
+	JSONObject(pairs []string) string
+	// JSONDocument returns the SQL of a JSON object of pairs of quoted keys and SQL values, as stored
+	JSONDocument(pairs []string) string
+	// JSONParam returns the SQL of a placeholder holding a JSON document
+	JSONParam() string
+	// TypedParam returns the SQL of a placeholder of sqlType within a JSON object
+	TypedParam(sqlType string) string
+	// JSONValue returns the SQL of a placeholder of sqlType as a JSON value for JSONSet
+	JSONValue(sqlType string) string
+	// JSONMerge returns the SQL of JSON object expr with the keys of JSON object patch
+	JSONMerge(expr, patch string) string
+	// JSONSet returns the SQL of JSON expr with the JSON value set at innerField
+	JSONSet(expr, innerField, value string) string
+
+	// JSONArrayAction returns the SQL of JSON array expr after action (JSONBArrAdd, JSONBArrAddUnique or JSONBArrRemove)
+	// elements are given as a JSON array placeholder
+	JSONArrayAction(expr string, action uint8) string
+	// JSONArrayHas returns the condition of array innerField of JSON expr having an element equal to value (a placeholder or text)
+	JSONArrayHas(expr, innerField, value string) string
+	// JSONArrayHasAny returns the condition of array innerField of JSON expr having any element within list (see ArrayParam)
+	// elements are compared as text
+	JSONArrayHasAny(expr, innerField, list string) string
+	// JSONHasKeys returns the condition of JSON expr having the keys of n placeholders, as operator (OpHasKey, OpHasAnyKey or OpHasAllKeys)
+	// string elements of arrays are matched as keys
+	JSONHasKeys(expr, operator string, n int) string
+	// JSONContains returns the condition of JSON expr containing the JSON document of a placeholder
+	JSONContains(expr string) string
+	// JSONArrayElements returns the join of the text elements of JSON array expr named alias, and the SQL of an element
+	JSONArrayElements(expr, alias string) (string, string)
+
+	// Search returns the condition of text matching the search placeholder in lang
+	Search(lang, text string) string
+	// SearchRank returns the relevance of text to the search placeholder in lang
+	SearchRank(lang, text string) string
+	// SearchHeadline returns a snippet of text highlighting the matches of the search placeholder in lang
+	SearchHeadline(lang, text string) string
+
+	// Include returns the JSON array of the objects of rows of table referenced by JSON array expr, named after name
+	// object refers to the related row as "rel", the join is added to the statement if any
+	Include(table, expr, object, name string) (string, string)
+}
@@ -0,0 +1,221 @@
+package somesql
+
+import (
+	"strings"
+)
+
+// SQLite renders SQLite with the JSON1 functions (3.38+), JSON documents are stored as TEXT
+// Full-text search matches the whole search text within fields (no stemming)
+var SQLite Dialect = sqliteDialect{}
+
+// sqliteDialect implements Dialect for SQLite
+type sqliteDialect struct{}
+
+// Placeholders implements Dialect
+func (sqliteDialect) Placeholders(sql string) string {
+	return strings.ReplaceAll(sql, "£", "?")
+}
+
+// Placeholder implements Dialect
+func (sqliteDialect) Placeholder(i int) string {
+	return `?`
+}
+
+// ArrayParam implements Dialect
+// arrays cannot be bound, values are bound one by one
+func (sqliteDialect) ArrayParam(values []interface{}) (string, []interface{}) {
+	return strings.TrimSuffix(strings.Repeat(`?,`, len(values)), `,`), values
+}
+
+// In implements Dialect
+func (sqliteDialect) In(lhs, list string, not bool) string {
+	if not {
+		return lhs + ` NOT IN (` + list + `)`
+	}
+
+	return lhs + ` IN (` + list + `)`
+}
+
+// Default implements Dialect
+// SQLite has no DEFAULT keyword within VALUES
+func (sqliteDialect) Default() string {
+	return ``
+}
+
+// Cast implements Dialect
+func (sqliteDialect) Cast(expr, sqlType string) string {
+	switch sqlType {
+	case "BOOLEAN":
+		return expr // booleans are integers
+	case "JSONB":
+		return `json(` + expr + `)`
+	case "INT":
+		sqlType = "INTEGER"
+	}
+
+	return `CAST(` + expr + ` AS ` + sqlType + `)`
+}
+
+// Compare implements Dialect
+// ILIKE is LIKE (case insensitive for ASCII), regular expressions require a REGEXP function to be registered
+func (sqliteDialect) Compare(lhs, operator, rhs string) string {
+	switch normalizeOperator(operator) {
+	case OpILike:
+		operator = OpLike
+	case OpNotILike:
+		operator = OpNotLike
+	case OpMatch:
+		operator = `REGEXP`
+	case OpNotMatch:
+		operator = `NOT REGEXP`
+	case OpIMatch:
+		operator, rhs = `REGEXP`, `'(£i)' || `+rhs
+	case OpNotIMatch:
+		operator, rhs = `NOT REGEXP`, `'(£i)' || `+rhs
+	}
+
+	return lhs + ` ` + operator + ` ` + rhs
+}
+
+// JSONAccessor implements Dialect
+func (sqliteDialect) JSONAccessor(expr, innerField string, asText bool) string {
+	operator := "->"
+	if asText {
+		operator = "->>"
+	}
+
+	return expr + operator + getJSONPath(innerField)
+}
+
+// JSONLiteral implements Dialect
+func (sqliteDialect) JSONLiteral(json string) string {
+	return `json('` + json + `')`
+}
+
+// JSONObject implements Dialect
+func (sqliteDialect) JSONObject(pairs []string) string {
+	return `json_object(` + strings.Join(pairs, `, `) + `)`
+}
+
+// JSONDocument implements Dialect
+func (sqliteDialect) JSONDocument(pairs []string) string {
+	return `json_object(` + strings.Join(pairs, `, `) + `)`
+}
+
+// JSONParam implements Dialect
+func (sqliteDialect) JSONParam() string {
+	return `json(?)`
+}
+
+// TypedParam implements Dialect
+func (d sqliteDialect) TypedParam(sqlType string) string {
+	if sqlType == "BOOLEAN" {
+		return `json(CASE WHEN ? THEN 'true' ELSE 'false' END)`
+	}
+
+	return d.Cast(`?`, sqlType)
+}
+
+// JSONValue implements Dialect
+func (d sqliteDialect) JSONValue(sqlType string) string {
+	return d.TypedParam(sqlType)
+}
+
+// JSONMerge implements Dialect
+// keys set to null within patch are removed
+func (sqliteDialect) JSONMerge(expr, patch string) string {
+	return `json_patch(` + expr + `, ` + patch + `)`
+}
+
+// JSONSet implements Dialect
+func (sqliteDialect) JSONSet(expr, innerField, value string) string {
+	return `json_set(` + expr + `, ` + getJSONPath(innerField) + `, ` + value + `)`
+}
+
+// JSONArrayAction implements Dialect
+// Duplicates within the elements added with JSONBArrAddUnique are kept
+func (sqliteDialect) JSONArrayAction(expr string, action uint8) string {
+	var (
+		current  = `COALESCE(` + expr + `, json('[]'))`
+		elements = `SELECT 0 "part", "key", "value", "type" FROM json_each(` + current + `)`
+	)
+
+	switch action {
+	case JSONBArrAddUnique:
+		elements += ` UNION ALL SELECT 1, "key", "value", "type" FROM json_each(?) WHERE "value" NOT IN (SELECT "value" FROM json_each(` + current + `))`
+	case JSONBArrRemove:
+		elements += ` WHERE "value" NOT IN (SELECT "value" FROM json_each(?))`
+	default:
+		elements += ` UNION ALL SELECT 1, "key", "value", "type" FROM json_each(?)`
+	}
+
+	return `(SELECT json_group_array(` + sqliteElement + `) FROM (` + elements + ` ORDER BY 1, 2))`
+}
+
+// JSONArrayHas implements Dialect
+func (sqliteDialect) JSONArrayHas(expr, innerField, value string) string {
+	return `EXISTS (SELECT 1 FROM json_each(` + expr + `, ` + getJSONPath(innerField) + `) WHERE "value" = ` + value + `)`
+}
+
+// JSONArrayHasAny implements Dialect
+func (sqliteDialect) JSONArrayHasAny(expr, innerField, list string) string {
+	return `EXISTS (SELECT 1 FROM json_each(` + expr + `, ` + getJSONPath(innerField) + `) WHERE "value" IN (` + list + `))`
+}
+
+// JSONHasKeys implements Dialect
+// keys of objects and text elements of arrays are matched, as with JSONB
+func (sqliteDialect) JSONHasKeys(expr, operator string, n int) string {
+	var (
+		placeholders = strings.TrimSuffix(strings.Repeat(`?,`, n), `,`)
+		key          = `CASE json_type(` + expr + `) WHEN 'array' THEN "value" ELSE "key" END`
+	)
+
+	if operator == OpHasAllKeys {
+		values := strings.TrimSuffix(strings.Repeat(`(?),`, n), `,`)
+		return `NOT EXISTS (SELECT 1 FROM (VALUES ` + values + `) "keys" WHERE "column1" NOT IN (SELECT ` + key + ` FROM json_each(` + expr + `)))`
+	}
+
+	return `EXISTS (SELECT 1 FROM json_each(` + expr + `) WHERE ` + key + ` IN (` + placeholders + `))`
+}
+
+// JSONContains implements Dialect
+// containment is evaluated on the top level of the documents: elements of arrays, pairs of objects or scalars
+func (sqliteDialect) JSONContains(expr string) string {
+	return `NOT EXISTS (SELECT 1 FROM json_each(json(?)) "v" WHERE NOT EXISTS (SELECT 1 FROM json_each(` + expr + `) "e" ` +
+		`WHERE "e"."value" = "v"."value" AND ("v"."key" IS NULL OR typeof("v"."key") = 'integer' OR "e"."key" = "v"."key")))`
+}
+
+// JSONArrayElements implements Dialect
+func (sqliteDialect) JSONArrayElements(expr, alias string) (string, string) {
+	return `CROSS JOIN json_each(` + expr + `) ` + quoteIdentifier(alias), quoteIdentifier(alias) + `."value"`
+}
+
+// Search implements Dialect
+func (sqliteDialect) Search(lang, text string) string {
+	return `instr(lower(` + text + `), lower(?)) > 0`
+}
+
+// SearchRank implements Dialect
+func (sqliteDialect) SearchRank(lang, text string) string {
+	return `(instr(lower(` + text + `), lower(?)) > 0)`
+}
+
+// SearchHeadline implements Dialect
+// the snippet starts a little before the first match
+func (sqliteDialect) SearchHeadline(lang, text string) string {
+	return `substr(` + text + `, max(instr(lower(` + text + `), lower(?)) - 30, 1), 160)`
+}
+
+// Include implements Dialect
+// related rows are aggregated within a correlated subquery
+func (sqliteDialect) Include(table, expr, object, name string) (string, string) {
+	return `(SELECT COALESCE(json_group_array(json(` + object + `)), json('[]')) FROM json_each(` + expr + `) "elem" INNER JOIN ` + table + ` "rel" ON "rel"."id" = "elem"."value")`, ""
+}
+
+// sqliteElement is the JSON value of an element of json_each
+const sqliteElement = `CASE "type" WHEN 'true' THEN json('true') WHEN 'false' THEN json('false') WHEN 'object' THEN json("value") WHEN 'array' THEN json("value") ELSE "value" END`
+
+// getJSONPath returns innerField as a JSON path i.e seo.title => '$.seo.title'
+func getJSONPath(innerField string) string {
+	return quoteLiteral(`$.` + innerField)
+}
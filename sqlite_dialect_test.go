@@ -0,0 +1,218 @@
+package somesql_test
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"testing"
+
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/assert"
+	"go.lsl.digital/lardwaz/somesql"
+)
+
+// sqliteSchema is the repo table of SQLite (see README)
+const sqliteSchema = `CREATE TABLE repo (
+    "id"         TEXT PRIMARY KEY NOT NULL,
+    "created_at" TEXT DEFAULT CURRENT_TIMESTAMP,
+    "updated_at" TEXT DEFAULT CURRENT_TIMESTAMP,
+    "owner_id"   TEXT NOT NULL DEFAULT '00000000-0000-0000-0000-000000000000',
+    "type"       TEXT NOT NULL DEFAULT 'article',
+    "data_en"    TEXT NOT NULL DEFAULT '{}',
+    "data_fr"    TEXT NOT NULL DEFAULT '{}'
+)`
+
+// newSQLite returns an in-memory SQLite database with the repo table, the test is skipped without cgo
+func newSQLite(t *testing.T) *sql.DB {
+	db, err := sql.Open("sqlite3", ":memory:")
+	if err == nil {
+		db.SetMaxOpenConns(1) // a single in-memory database
+		_, err = db.Exec(sqliteSchema)
+	}
+	if err != nil {
+		t.Skipf("SQLite unavailable: %v", err)
+	}
+
+	return db
+}
+
+func TestSQLite_AsSQL(t *testing.T) {
+	type testCase struct {
+		name           string
+		query          somesql.Statement
+		expectedSQL    string
+		expectedValues []interface{}
+	}
+
+	tests := []testCase{
+		{
+			name:           "SELECT fields with conditions",
+			query:          somesql.NewSelect("en").Fields("id", "data.title", "data.seo.title").Where(somesql.And("en", "relations.tags", "=", "x")).Where(somesql.And("en", "data.featured", "=", true)).Order("data.title", true),
+			expectedSQL:    `SELECT "id", json_object('title', "data_en"->'$.title', 'seo', json_object('title', "data_en"->'$.seo.title')) "data" FROM repo WHERE (EXISTS (SELECT 1 FROM json_each("data_en", '$.tags') WHERE "value" = ?)) AND ("data_en"->>'$.featured') = ? ORDER BY "data_en"->>'$.title' ASC LIMIT 10`,
+			expectedValues: []interface{}{"x", true},
+		},
+		{
+			name:           "SELECT IN",
+			query:          somesql.NewSelect("en").Where(somesql.AndIn("en", "relations.tags", []string{"y", "z"})).Where(somesql.AndIn("en", "type", []string{"a", "b"})),
+			expectedSQL:    `SELECT "id", "created_at", "updated_at", "owner_id", "type", "data_en" FROM repo WHERE EXISTS (SELECT 1 FROM json_each("data_en", '$.tags') WHERE "value" IN (?,?)) AND "type" IN (?,?) LIMIT 10`,
+			expectedValues: []interface{}{"y", "z", "a", "b"},
+		},
+		{
+			name:           "SELECT NOT IN",
+			query:          somesql.NewSelect("en").Fields("id").Where(somesql.AndNotIn("en", "data.seo.keywords", []string{"y", "z"})).Where(somesql.AndNotIn("en", "owner_id", []int64{1, 2})),
+			expectedSQL:    `SELECT "id" FROM repo WHERE NOT EXISTS (SELECT 1 FROM json_each("data_en", '$.seo.keywords') WHERE "value" IN (?,?)) AND "owner_id" NOT IN (?,?) LIMIT 10`,
+			expectedValues: []interface{}{"y", "z", int64(1), int64(2)},
+		},
+		{
+			name:           "SELECT include relations",
+			query:          somesql.NewSelect("en").Fields("id").Include("relations.author", "id", "data.name").Where(somesql.And("en", "type", "=", "article")),
+			expectedSQL:    `SELECT "id", (SELECT COALESCE(json_group_array(json(json_object('id', "rel"."id", 'data', json_object('name', "rel"."data_en"->'$.name')))), json('[]')) FROM json_each(repo."data_en"->'$.author') "elem" INNER JOIN repo "rel" ON "rel"."id" = "elem"."value") "relations.author" FROM repo WHERE "type" = ? LIMIT 10`,
+			expectedValues: []interface{}{"article"},
+		},
+		{
+			name:           "SELECT COUNT(*) GROUP BY relations",
+			query:          somesql.NewSelect("en").GroupBy("relations.tags").Count("n").Order("n", false),
+			expectedSQL:    `SELECT "tags"."value" "tags", COUNT(*) "n" FROM repo CROSS JOIN json_each("data_en"->'$.tags') "tags" GROUP BY "tags"."value" ORDER BY "n" DESC LIMIT 10`,
+			expectedValues: []interface{}{},
+		},
+		{
+			name:           "SELECT search",
+			query:          somesql.NewSelect("en").Fields("id").Where(somesql.AndSearch("en", "foo", "data.title")).OrderRank("foo", "data.title"),
+			expectedSQL:    `SELECT "id" FROM repo WHERE instr(lower(COALESCE("data_en"->>'$.title', '')), lower(?)) > 0 ORDER BY (instr(lower(COALESCE("data_en"->>'$.title', '')), lower(?)) > 0) DESC LIMIT 10`,
+			expectedValues: []interface{}{"foo", "foo"},
+		},
+		{
+			name:           "SELECT fallback",
+			query:          somesql.NewSelect("fr").Fallback("en").Fields("id", "data.title").Where(somesql.And("fr", "data.title", "=", "abc")),
+			expectedSQL:    `SELECT "id", json_object('title', COALESCE(NULLIF("data_fr", json('{}')), "data_en")->'$.title') "data", CASE WHEN NULLIF("data_fr", json('{}')) IS NOT NULL THEN 'fr' ELSE 'en' END "data_lang" FROM repo WHERE COALESCE(NULLIF("data_fr", json('{}')), "data_en")->>'$.title' = ? LIMIT 10`,
+			expectedValues: []interface{}{"abc"},
+		},
+		{
+			name:           "SELECT operators",
+			query:          somesql.NewSelect("en").Fields("id").Where(somesql.AndILike("en", "data.title", "a%")).Where(somesql.And("en", "data.title", somesql.OpIMatch, "^a")).Where(somesql.AndIsNull("en", "owner_id")).Where(somesql.AndBetween("en", "data.views", 1, 10)),
+			expectedSQL:    `SELECT "id" FROM repo WHERE "data_en"->>'$.title' LIKE ? AND "data_en"->>'$.title' REGEXP '(?i)' || ? AND "owner_id" IS NULL AND CAST(("data_en"->>'$.views') AS NUMERIC) BETWEEN ? AND ? LIMIT 10`,
+			expectedValues: []interface{}{"a%", "^a", 1, 10},
+		},
+		{
+			name:           "SELECT key existence and containment",
+			query:          somesql.NewSelect("en").Fields("id").Where(somesql.AndHasKey("en", "data.seo", "title")).Where(somesql.AndHasAllKeys("en", "relations.tags", "a", "b")).Where(somesql.AndContains("en", "relations.tags", []string{"a"})),
+			expectedSQL:    `SELECT "id" FROM repo WHERE EXISTS (SELECT 1 FROM json_each("data_en"->'$.seo') WHERE CASE json_type("data_en"->'$.seo') WHEN 'array' THEN "value" ELSE "key" END IN (?)) AND NOT EXISTS (SELECT 1 FROM (VALUES (?),(?)) "keys" WHERE "column1" NOT IN (SELECT CASE json_type("data_en"->'$.tags') WHEN 'array' THEN "value" ELSE "key" END FROM json_each("data_en"->'$.tags'))) AND NOT EXISTS (SELECT 1 FROM json_each(json(?)) "v" WHERE NOT EXISTS (SELECT 1 FROM json_each("data_en"->'$.tags') "e" WHERE "e"."value" = "v"."value" AND ("v"."key" IS NULL OR typeof("v"."key") = 'integer' OR "e"."key" = "v"."key"))) LIMIT 10`,
+			expectedValues: []interface{}{"title", "a", "b", `["a"]`},
+		},
+		{
+			name:           "INSERT",
+			query:          somesql.NewInsert("en").Fields(somesql.NewFields().ID("uuid").Type("article").Set("data.title", "abc")),
+			expectedSQL:    `INSERT INTO repo ("id", "type", "data_en") VALUES (?, ?, ?)`,
+			expectedValues: []interface{}{"uuid", "article", `{"title":"abc"}`},
+		},
+		{
+			name:           "INSERT ON CONFLICT merge",
+			query:          somesql.NewInsert("en").Fields(somesql.NewFields().ID("uuid").Set("data.title", "abc")).OnConflict(somesql.ConflictMerge, "id"),
+			expectedSQL:    `INSERT INTO repo ("id", "data_en") VALUES (?, ?) ON CONFLICT ("id") DO UPDATE SET "data_en" = json_patch(repo."data_en", EXCLUDED."data_en")`,
+			expectedValues: []interface{}{"uuid", `{"title":"abc"}`},
+		},
+		{
+			name:           "UPDATE data fields",
+			query:          somesql.NewUpdate("en").Fields(somesql.NewFields().Set("data.title", "abc").Set("data.featured", false).Set("data.seo.title", "def")).Where(somesql.And("en", "id", "=", "uuid")),
+			expectedSQL:    `UPDATE repo SET "data_en" = json_set(json_set(json_patch("data_en", json_object('title', CAST(? AS TEXT), 'featured', json(CASE WHEN ? THEN 'true' ELSE 'false' END))), '$.seo', COALESCE("data_en"->'$.seo', json('{}'))), '$.seo.title', CAST(? AS TEXT)) WHERE "id" = ?`,
+			expectedValues: []interface{}{"abc", false, "def", "uuid"},
+		},
+		{
+			name:           "UPDATE replace",
+			query:          somesql.NewUpdate("en").Fields(somesql.NewFields().Set("data.title", "abc").Set("data.views", 2)).Replace(true),
+			expectedSQL:    `UPDATE repo SET "data_en" = json(json_object('title', CAST(? AS TEXT), 'views', CAST(? AS INTEGER)))`,
+			expectedValues: []interface{}{"abc", 2},
+		},
+		{
+			name:           "UPDATE remove from relations",
+			query:          somesql.NewUpdate("en").Fields(somesql.NewFields().Remove("relations.tags", "a")).Where(somesql.And("en", "id", "=", "uuid")),
+			expectedSQL:    `UPDATE repo SET "data_en" = json_set("data_en", '$.tags', (SELECT json_group_array(CASE "type" WHEN 'true' THEN json('true') WHEN 'false' THEN json('false') WHEN 'object' THEN json("value") WHEN 'array' THEN json("value") ELSE "value" END) FROM (SELECT 0 "part", "key", "value", "type" FROM json_each(COALESCE("data_en"->'$.tags', json('[]'))) WHERE "value" NOT IN (SELECT "value" FROM json_each(?)) ORDER BY 1, 2))) WHERE "id" = ?`,
+			expectedValues: []interface{}{`["a"]`, "uuid"},
+		},
+		{
+			name:           "DELETE IN query",
+			query:          somesql.NewDelete("en").Where(somesql.AndInQuery("en", "id", somesql.NewSelect("en").Fields("id").Where(somesql.And("en", "type", "=", "author")))),
+			expectedSQL:    `DELETE FROM repo WHERE "id" IN (SELECT "id" FROM repo WHERE "type" = ?)`,
+			expectedValues: []interface{}{"author"},
+		},
+	}
+
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.query.SetDialect(somesql.SQLite)
+			tt.query.ToSQL()
+
+			assert.Equal(t, tt.expectedSQL, tt.query.GetSQL(), fmt.Sprintf("%02d. %s :: SQL invalid", i+1, tt.name))
+			assert.Equal(t, tt.expectedValues, tt.query.GetValues(), fmt.Sprintf("%02d. %s :: values invalid", i+1, tt.name))
+		})
+	}
+}
+
+func TestSQLite_InsertBatch(t *testing.T) {
+	db := newSQLite(t)
+	defer db.Close()
+
+	insert := somesql.NewInsert("en", db).Batch(
+		somesql.NewFields().ID("a1").Type("page").Set("data.title", "A"),
+		somesql.NewFields().ID("a2").Set("data.title", "B"),
+		somesql.NewFields().ID("a3").Type("page"),
+		somesql.NewFields().ID("a4").Set("data.title", "D"),
+	)
+	insert.SetDialect(somesql.SQLite)
+
+	_, _, err := insert.Build()
+	assert.True(t, errors.Is(err, somesql.ErrValuesMismatch), "rows of different columns are not a single statement")
+
+	assert.Nil(t, insert.Exec(true), "rows are inserted by set of columns")
+
+	rows, err := db.Query(`SELECT "id", "type", "owner_id", "data_en", "created_at" IS NOT NULL FROM repo ORDER BY "id"`)
+	assert.Nil(t, err)
+	defer rows.Close()
+
+	var got [][]interface{}
+	for rows.Next() {
+		var (
+			id, typ, ownerID, data string
+			hasCreatedAt           bool
+		)
+		assert.Nil(t, rows.Scan(&id, &typ, &ownerID, &data, &hasCreatedAt))
+		got = append(got, []interface{}{id, typ, ownerID, data, hasCreatedAt})
+	}
+
+	nilUUID := "00000000-0000-0000-0000-000000000000"
+	assert.Equal(t, [][]interface{}{
+		{"a1", "page", nilUUID, `{"title":"A"}`, true},
+		{"a2", "article", nilUUID, `{"title":"B"}`, true},
+		{"a3", "page", nilUUID, `{}`, true},
+		{"a4", "article", nilUUID, `{"title":"D"}`, true},
+	}, got, "missing columns are set to their default")
+}
+
+func TestSQLite_IncludeFallback(t *testing.T) {
+	db := newSQLite(t)
+	defer db.Close()
+
+	insert := somesql.NewInsert("en", db).Batch(
+		somesql.NewFields().ID("u1").Type("author").Set("data.name", "Alice"),
+		somesql.NewFields().ID("a1").Set("data.title", "Hello").Set("relations.author", []string{"u1"}),
+	)
+	insert.SetDialect(somesql.SQLite)
+	assert.Nil(t, insert.Exec(true))
+
+	query := somesql.NewSelect("fr", db).Fallback("en").Fields("id", "data.title").Include("relations.author", "id", "data.name").Where(somesql.And("fr", "id", "=", "a1"))
+	query.SetDialect(somesql.SQLite)
+
+	doc, err := query.One()
+	assert.Nil(t, err)
+	assert.Equal(t, "en", doc.Lang, "untranslated documents are read from the fallback lang")
+	if assert.Len(t, doc.Included["author"], 1, "relations are read from the fallback lang") {
+		assert.Equal(t, "Alice", doc.Included["author"][0].Data["name"])
+	}
+
+	query = somesql.NewSelect("en", db).Fallback("en").Fields("id").Where(somesql.And("en", "id", "=", "a1"))
+	query.SetDialect(somesql.SQLite)
+
+	doc, err = query.One()
+	assert.Nil(t, err, "the lang of Select within its fallback chain")
+	assert.Equal(t, "en", doc.Lang)
+}
@@ -0,0 +1,253 @@
+package somesql
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+)
+
+// TagName is the struct tag used to map struct fields to Fields
+// i.e `somesql:"id"`, `somesql:"data.title"`, `somesql:"relations.author,omitempty"`
+const TagName = "somesql"
+
+// structField represents a tagged struct field
+type structField struct {
+	index     int
+	field     string
+	omitEmpty bool
+}
+
+// getStructFields returns the tagged fields of a struct type
+func getStructFields(t reflect.Type) []structField {
+	var fields []structField
+
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.PkgPath != "" { // unexported
+			continue
+		}
+
+		tag, ok := f.Tag.Lookup(TagName)
+		if !ok || tag == "-" {
+			continue
+		}
+
+		parts := strings.Split(tag, ",")
+		if parts[0] == "" {
+			continue
+		}
+
+		sf := structField{index: i, field: parts[0]}
+		for _, opt := range parts[1:] {
+			if opt == "omitempty" {
+				sf.omitEmpty = true
+			}
+		}
+		fields = append(fields, sf)
+	}
+
+	return fields
+}
+
+// structValue returns the struct behind v (dereferencing pointers)
+func structValue(v interface{}) (reflect.Value, error) {
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Ptr {
+		if rv.IsNil() {
+			return rv, errors.New("nil pointer")
+		}
+		rv = rv.Elem()
+	}
+
+	if rv.Kind() != reflect.Struct {
+		return rv, errors.New("struct expected")
+	}
+
+	return rv, nil
+}
+
+// StructFields returns the field names declared by the tags of struct v
+// It can be used as projection i.e NewSelect("en").Fields(StructFields(Article{})...)
+func StructFields(v interface{}) []string {
+	rv, err := structValue(v)
+	if err != nil {
+		return nil
+	}
+
+	var fields []string
+	for _, sf := range getStructFields(rv.Type()) {
+		fields = append(fields, sf.field)
+	}
+
+	return fields
+}
+
+// NewFieldsFromStruct returns new Fields from the tagged fields of struct v
+func NewFieldsFromStruct(v interface{}) (Fields, error) {
+	rv, err := structValue(v)
+	if err != nil {
+		return nil, err
+	}
+
+	fields := NewFields()
+	for _, sf := range getStructFields(rv.Type()) {
+		fv := rv.Field(sf.index)
+		if sf.omitEmpty && isEmptyValue(fv) {
+			continue
+		}
+		fields.Set(sf.field, fv.Interface())
+	}
+
+	return fields, nil
+}
+
+func isEmptyValue(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return v.Len() == 0
+	case reflect.Ptr, reflect.Interface:
+		return v.IsNil()
+	}
+
+	return reflect.DeepEqual(v.Interface(), reflect.Zero(v.Type()).Interface())
+}
+
+// value returns the value of a field in the document
+func (d Document) value(field string) (interface{}, bool) {
+	switch field {
+	case FieldID:
+		return d.ID, true
+	case FieldCreatedAt:
+		return d.CreatedAt, true
+	case FieldUpdatedAt:
+		return d.UpdatedAt, true
+	case FieldOwnerID:
+		return d.OwnerID, true
+	case FieldType:
+		return d.Type, true
+	case FieldData:
+		return d.Data, d.Data != nil
+	}
+
+	if innerField, ok := GetInnerField(FieldData, field); ok {
+		return getNestedValue(d.Data, innerField)
+	} else if innerField, ok := GetInnerField(FieldRelations, field); ok {
+		return getNestedValue(d.Data, innerField)
+	} else if parent, innerField, ok := getInnerFieldAny(field); ok {
+		if m, ok := d.Meta[parent].(map[string]interface{}); ok {
+			return getNestedValue(m, innerField)
+		}
+		return nil, false
+	}
+
+	v, ok := d.Meta[field]
+	return v, ok
+}
+
+// Decode assigns the document fields to the tagged fields of struct pointed by v
+func (d Document) Decode(v interface{}) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return errors.New("non-nil pointer expected")
+	}
+
+	rv, err := structValue(v)
+	if err != nil {
+		return err
+	}
+
+	for _, sf := range getStructFields(rv.Type()) {
+		val, ok := d.value(sf.field)
+		if !ok || val == nil {
+			continue
+		}
+
+		if err := assignValue(rv.Field(sf.index), val); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// assignValue sets val to dst directly if assignable, else through JSON
+func assignValue(dst reflect.Value, val interface{}) error {
+	rval := reflect.ValueOf(val)
+	if rval.Type().AssignableTo(dst.Type()) {
+		dst.Set(rval)
+		return nil
+	}
+
+	jsonBytes, err := json.Marshal(val)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(jsonBytes, dst.Addr().Interface())
+}
+
+// OneInto decodes the first Document matching Select into struct pointed by dst
+// ErrNotFound is returned if there is none
+func (s Select) OneInto(dst interface{}) error {
+	return s.OneIntoContext(context.Background(), dst)
+}
+
+// OneIntoContext decodes the first Document matching Select into struct pointed by dst
+// ErrNotFound is returned if there is none
+func (s Select) OneIntoContext(ctx context.Context, dst interface{}) error {
+	doc, err := s.OneContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	return doc.Decode(dst)
+}
+
+// AllInto decodes all Documents matching Select into slice of structs pointed by dst
+func (s Select) AllInto(dst interface{}) error {
+	return s.AllIntoContext(context.Background(), dst)
+}
+
+// AllIntoContext decodes all Documents matching Select into slice of structs pointed by dst
+func (s Select) AllIntoContext(ctx context.Context, dst interface{}) error {
+	return decodeAll(dst, func(fn func(Document) error) error {
+		return s.EachContext(ctx, fn)
+	})
+}
+
+// decodeAll decodes the Documents iterated by each into slice of structs pointed by dst
+func decodeAll(dst interface{}, each func(fn func(Document) error) error) error {
+	rv := reflect.ValueOf(dst)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() || rv.Elem().Kind() != reflect.Slice {
+		return errors.New("non-nil pointer to slice expected")
+	}
+
+	var (
+		slice    = rv.Elem()
+		elemType = slice.Type().Elem()
+		isPtr    = elemType.Kind() == reflect.Ptr
+	)
+
+	if isPtr {
+		elemType = elemType.Elem()
+	}
+
+	slice.Set(reflect.MakeSlice(slice.Type(), 0, 0))
+
+	return each(func(d Document) error {
+		elem := reflect.New(elemType)
+		if err := d.Decode(elem.Interface()); err != nil {
+			return err
+		}
+
+		if isPtr {
+			slice.Set(reflect.Append(slice, elem))
+		} else {
+			slice.Set(reflect.Append(slice, elem.Elem()))
+		}
+
+		return nil
+	})
+}
@@ -0,0 +1,100 @@
+package somesql_test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.lsl.digital/lardwaz/somesql"
+)
+
+type article struct {
+	ID        string    `somesql:"id"`
+	CreatedAt time.Time `somesql:"created_at,omitempty"`
+	Type      string    `somesql:"type"`
+	Title     string    `somesql:"data.title"`
+	Views     int       `somesql:"data.views,omitempty"`
+	Authors   []string  `somesql:"relations.author"`
+	Ignored   string    `somesql:"-"`
+	Untagged  string
+}
+
+func TestStructFields(t *testing.T) {
+	assert.Equal(t, []string{"id", "created_at", "type", "data.title", "data.views", "relations.author"}, somesql.StructFields(article{}))
+	assert.Equal(t, []string{"id", "created_at", "type", "data.title", "data.views", "relations.author"}, somesql.StructFields(&article{}))
+	assert.Nil(t, somesql.StructFields("article"))
+}
+
+func TestNewFieldsFromStruct(t *testing.T) {
+	type testCase struct {
+		name           string
+		value          interface{}
+		expectedSQL    string
+		expectedValues []interface{}
+	}
+
+	tests := []testCase{
+		{
+			name:           "INSERT from struct",
+			value:          article{ID: "1", Type: "article", Title: "abc", Views: 2, Authors: []string{"a", "b"}, Ignored: "x", Untagged: "y"},
+			expectedSQL:    `INSERT INTO repo ("id", "type", "data_en") VALUES ($1, $2, $3)`,
+			expectedValues: []interface{}{"1", "article", `{"author":["a","b"],"title":"abc","views":2}`},
+		},
+		{
+			name:           "INSERT from struct pointer (omitempty)",
+			value:          &article{ID: "1", Type: "article", Title: "abc"},
+			expectedSQL:    `INSERT INTO repo ("id", "type", "data_en") VALUES ($1, $2, $3)`,
+			expectedValues: []interface{}{"1", "article", `{"author":[],"title":"abc"}`},
+		},
+	}
+
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fields, err := somesql.NewFieldsFromStruct(tt.value)
+			assert.NoError(t, err, fmt.Sprintf("%d: Unexpected error", i+1))
+
+			query := somesql.NewInsert("en").Fields(fields)
+			query.ToSQL()
+
+			assert.Equal(t, tt.expectedSQL, query.GetSQL(), fmt.Sprintf("%d: SQL invalid", i+1))
+			assert.Equal(t, tt.expectedValues, query.GetValues(), fmt.Sprintf("%d: Values invalid", i+1))
+		})
+	}
+
+	_, err := somesql.NewFieldsFromStruct(1)
+	assert.Error(t, err, "non struct must fail")
+}
+
+func TestDocumentDecode(t *testing.T) {
+	now := time.Date(2009, time.November, 10, 23, 0, 0, 0, time.UTC)
+	doc := somesql.Document{
+		ID:        "1",
+		CreatedAt: now,
+		Type:      "article",
+		Data: map[string]interface{}{
+			"title":  "abc",
+			"views":  float64(2),
+			"author": []interface{}{"a", "b"},
+		},
+	}
+
+	var a article
+	assert.NoError(t, doc.Decode(&a))
+	assert.Equal(t, article{ID: "1", CreatedAt: now, Type: "article", Title: "abc", Views: 2, Authors: []string{"a", "b"}}, a)
+
+	assert.Error(t, doc.Decode(a), "non pointer must fail")
+
+	type seo struct {
+		Title   string `somesql:"data.seo.title"`
+		Caption string `somesql:"data.seo.image.caption"`
+	}
+
+	var o seo
+	doc.Data["seo"] = map[string]interface{}{"title": "def", "image": map[string]interface{}{"caption": "ghi"}}
+	assert.NoError(t, doc.Decode(&o))
+	assert.Equal(t, seo{Title: "def", Caption: "ghi"}, o)
+
+	doc.Data["views"] = "many"
+	assert.Error(t, doc.Decode(&a), "invalid type must fail")
+}
@@ -0,0 +1,205 @@
+package somesql
+
+import "strings"
+
+// Columns of the tables other than repo
+const (
+	FieldRepoID          string = "repo_id"
+	FieldPath            string = "path"
+	FieldLang            string = "lang"
+	FieldArchivedAt      string = "archived_at"
+	FieldArchive         string = "archive"
+	FieldStatus          string = "status"
+	FieldDeckMachineName string = "deck_machine_name"
+	FieldPosition        string = "position"
+	FieldEntity          string = "entity"
+	FieldEntityType      string = "entity_type"
+	FieldDateTime        string = "date_time"
+	FieldAction          string = "action"
+	FieldCardID          string = "card_id"
+)
+
+// Table represents a table in store
+// MetaFields are plain columns whereas JSONBFields are JSONB columns
+// which inner fields can be accessed with a dot i.e data.title
+// Langs are the languages data is stored in, as data_<lang> columns
+type Table struct {
+	Name        string
+	MetaFields  []string
+	JSONBFields []string
+	Langs       []string
+}
+
+// Tables variables
+var (
+	TableRepo = Table{
+		Name:        "repo",
+		MetaFields:  []string{FieldID, FieldCreatedAt, FieldUpdatedAt, FieldOwnerID, FieldType},
+		JSONBFields: []string{FieldData},
+		Langs:       []string{"en", "fr"},
+	}
+	TableSlugs = Table{
+		Name:       "slugs",
+		MetaFields: []string{FieldRepoID, FieldPath, FieldLang},
+	}
+	TableArchives = Table{
+		Name:        "archives",
+		MetaFields:  []string{FieldID, FieldRepoID, FieldArchivedAt},
+		JSONBFields: []string{FieldArchive},
+	}
+	TableCards = Table{
+		Name:       "cards",
+		MetaFields: []string{FieldID, FieldStatus, FieldDeckMachineName, FieldPosition, FieldEntity, FieldEntityType},
+	}
+	TableCardSchedules = Table{
+		Name:       "cardschedules",
+		MetaFields: []string{FieldID, FieldStatus, FieldDateTime, FieldAction, FieldCardID},
+	}
+)
+
+// NewTable returns a new Table
+func NewTable(name string, metaFields []string, jsonbFields ...string) Table {
+	return Table{
+		Name:        name,
+		MetaFields:  metaFields,
+		JSONBFields: jsonbFields,
+	}
+}
+
+// Fields returns all fields of the table (meta fields first)
+func (t Table) Fields() []string {
+	fields := make([]string, 0, len(t.MetaFields)+len(t.JSONBFields))
+	fields = append(fields, t.MetaFields...)
+	fields = append(fields, t.JSONBFields...)
+
+	return fields
+}
+
+// IsMeta returns true if field is a meta field of the table
+func (t Table) IsMeta(field string) bool {
+	for _, f := range t.MetaFields {
+		if f == field {
+			return true
+		}
+	}
+
+	return false
+}
+
+// IsJSONB returns true if field is a JSONB field of the table
+// relations are stored within data
+func (t Table) IsJSONB(field string) bool {
+	if IsFieldRelations(field) {
+		field = FieldData
+	}
+
+	for _, f := range t.JSONBFields {
+		if f == field {
+			return true
+		}
+	}
+
+	return false
+}
+
+// GetInnerField returns the JSONB parent and inner field of a dot-separated field if parent is a JSONB field of the table
+func (t Table) GetInnerField(field string) (string, string, bool) {
+	parent, innerField, ok := getInnerFieldAny(field)
+	if !ok || !t.IsJSONB(parent) {
+		return "", "", false
+	}
+
+	return parent, innerField, true
+}
+
+// getTableClause returns the table of a statement, named alias if any
+func getTableClause(t Table, alias string) string {
+	if alias == "" {
+		return t.Name
+	}
+
+	return t.Name + ` AS ` + quoteIdentifier(alias)
+}
+
+// GetLangField returns the column name of a field for lang
+// data (and relations stored within) is stored per language i.e data_<lang>
+func GetLangField(field, lang string) string {
+	if IsFieldData(field) || IsFieldRelations(field) {
+		return GetLangFieldData(lang)
+	}
+
+	return field
+}
+
+// getLangChain returns lang followed by the fallback langs, without duplicates
+func getLangChain(lang string, fallback []string) []string {
+	var (
+		langs  = []string{lang}
+		isSeen = map[string]bool{lang: true}
+	)
+
+	for _, l := range fallback {
+		if !isSeen[l] {
+			isSeen[l] = true
+			langs = append(langs, l)
+		}
+	}
+
+	return langs
+}
+
+// getFallbackColumn returns the SQL of the column of field for lang
+// data is read from the first language of the fallback chain having any
+func getFallbackColumn(d Dialect, field, lang string, fallback []string) string {
+	return getQualifiedFallbackColumn(d, "", field, lang, fallback)
+}
+
+// getQualifiedFallbackColumn returns the SQL of the column of field for lang, qualified with the table or alias qualifier
+// data is read from the first language of the fallback chain having any
+func getQualifiedFallbackColumn(d Dialect, qualifier, field, lang string, fallback []string) string {
+	var (
+		langs   = getLangChain(lang, fallback)
+		columns []string
+	)
+
+	if qualifier != "" {
+		qualifier += "."
+	}
+
+	if !(IsFieldData(field) || IsFieldRelations(field)) || len(langs) == 1 {
+		return qualifier + quoteIdentifier(GetLangField(field, lang))
+	}
+
+	for _, l := range langs[:len(langs)-1] {
+		columns = append(columns, `NULLIF(`+qualifier+quoteIdentifier(GetLangFieldData(l))+`, `+d.JSONLiteral("{}")+`)`)
+	}
+	columns = append(columns, qualifier+quoteIdentifier(GetLangFieldData(langs[len(langs)-1])))
+
+	return `COALESCE(` + strings.Join(columns, `, `) + `)`
+}
+
+// getFallbackAccessor returns the SQL to access innerField of field for lang
+// inner fields of data are read from the data of the fallback chain (see getFallbackColumn), as reported by getFallbackLang
+func getFallbackAccessor(d Dialect, field, lang string, fallback []string, innerField string, asText bool) string {
+	return d.JSONAccessor(getFallbackColumn(d, field, lang, fallback), innerField, asText)
+}
+
+// getFallbackLang returns the SQL of the first language of the fallback chain having data
+func getFallbackLang(d Dialect, lang string, fallback []string) string {
+	var (
+		langs = getLangChain(lang, fallback)
+		buff  strings.Builder
+	)
+
+	if len(langs) == 1 {
+		return quoteLiteral(lang)
+	}
+
+	buff.WriteString(`CASE`)
+	for _, l := range langs[:len(langs)-1] {
+		buff.WriteString(` WHEN NULLIF(` + quoteIdentifier(GetLangFieldData(l)) + `, ` + d.JSONLiteral("{}") + `) IS NOT NULL THEN ` + quoteLiteral(l))
+	}
+	buff.WriteString(` ELSE ` + quoteLiteral(langs[len(langs)-1]) + ` END`)
+
+	return buff.String()
+}
@@ -0,0 +1,143 @@
+package somesql
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Errors of unsafe input, reported by Build as the other errors of the builders
+var (
+	// ErrInvalidLang is returned for languages which are neither within Languages nor the languages of the table
+	ErrInvalidLang = errors.New("language not allowed")
+	// ErrInvalidIdentifier is returned for inner fields and aliases which are not made of letters, digits, _ and -
+	ErrInvalidIdentifier = errors.New("invalid identifier")
+	// ErrInvalidFunction is returned for SQL functions which are not within Functions
+	ErrInvalidFunction = errors.New("function not allowed")
+	// ErrInvalidOperator is returned for operators which are not within Operators
+	ErrInvalidOperator = errors.New("operator not allowed")
+)
+
+// Languages are the languages statements are built in, besides the languages of their table (Table.Langs)
+// Data is stored per language as data_<lang> columns
+var Languages = []string{"en", "fr"}
+
+// Functions are the SQL functions permitted as FieldFunction and ValueFunction of conditions (case insensitive)
+var Functions = map[string]bool{
+	"LOWER":  true,
+	"UPPER":  true,
+	"TRIM":   true,
+	"LENGTH": true,
+}
+
+// Operators are the operators permitted within conditions (case insensitive)
+var Operators = map[string]bool{
+	OpEqual:        true,
+	OpNotEqual:     true,
+	"!=":           true,
+	OpLess:         true,
+	OpLessEqual:    true,
+	OpGreater:      true,
+	OpGreaterEqual: true,
+	OpLike:         true,
+	OpNotLike:      true,
+	OpILike:        true,
+	OpNotILike:     true,
+	OpBetween:      true,
+	OpNotBetween:   true,
+	OpIsNull:       true,
+	OpIsNotNull:    true,
+	OpHasKey:       true,
+	OpHasAnyKey:    true,
+	OpHasAllKeys:   true,
+	OpContains:     true,
+	OpMatch:        true,
+	OpIMatch:       true,
+	OpNotMatch:     true,
+	OpNotIMatch:    true,
+}
+
+// identifierRegexp matches the keys of inner fields and aliases
+var identifierRegexp = regexp.MustCompile(`^[\p{L}\p{N}_-]+$`)
+
+// checkLang returns ErrInvalidLang if lang is not allowed for table
+// tables without data are not stored per language
+func checkLang(table Table, lang string) error {
+	if !table.IsJSONB(FieldData) {
+		return nil
+	}
+
+	for _, langs := range [][]string{table.Langs, Languages} {
+		for _, l := range langs {
+			if l == lang {
+				return nil
+			}
+		}
+	}
+
+	return fmt.Errorf("%w: %q", ErrInvalidLang, lang)
+}
+
+// checkLangs returns the errors of langs for table
+func checkLangs(table Table, langs ...string) []error {
+	var errs []error
+
+	for _, l := range langs {
+		if err := checkLang(table, l); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errs
+}
+
+// checkIdentifier returns ErrInvalidIdentifier on field if any key of name (dot-separated) is not a valid identifier
+func checkIdentifier(field, name string) error {
+	for _, key := range strings.Split(name, ".") {
+		if !identifierRegexp.MatchString(key) {
+			return FieldError{Field: field, Err: ErrInvalidIdentifier}
+		}
+	}
+
+	return nil
+}
+
+// checkFunction returns ErrInvalidFunction on field if function is not within Functions
+// aggregate functions are permitted as well if aggregate is true (HAVING)
+func checkFunction(field, function string, aggregate bool) error {
+	if function == None || Functions[strings.ToUpper(function)] || aggregate && isAggregateFunction(function) {
+		return nil
+	}
+
+	return FieldError{Field: field, Err: fmt.Errorf("%w: %q", ErrInvalidFunction, function)}
+}
+
+// isAggregateFunction returns true if function is one of the aggregate functions (case insensitive)
+func isAggregateFunction(function string) bool {
+	switch strings.ToUpper(function) {
+	case AggCount, AggSum, AggAvg, AggMin, AggMax:
+		return true
+	}
+
+	return false
+}
+
+// checkOperator returns ErrInvalidOperator on field if operator is not within Operators
+func checkOperator(field, operator string) error {
+	if Operators[normalizeOperator(operator)] {
+		return nil
+	}
+
+	return FieldError{Field: field, Err: fmt.Errorf("%w: %q", ErrInvalidOperator, operator)}
+}
+
+// quoteLiteral returns s as a SQL string literal, single quotes being doubled
+func quoteLiteral(s string) string {
+	return `'` + strings.ReplaceAll(s, `'`, `''`) + `'`
+}
+
+// quoteIdentifier returns s as a SQL identifier, double quotes being doubled
+func quoteIdentifier(s string) string {
+	return `"` + strings.ReplaceAll(s, `"`, `""`) + `"`
+}
@@ -0,0 +1,205 @@
+package somesql_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.lsl.digital/lardwaz/somesql"
+)
+
+func TestStatement_Validation(t *testing.T) {
+	type testCase struct {
+		name          string
+		query         somesql.Statement
+		expectedError error
+	}
+
+	tests := []testCase{
+		{
+			name:  "valid identifiers",
+			query: somesql.NewSelect("fr").Fallback("en").Fields("id", "data.seo.meta-title", "data.légende_2").Where(somesql.And("fr", "data.title", "LIKE", "a%", "LOWER", "lower")).Order("views", false),
+		},
+		{
+			name:          "lang not allowed",
+			query:         somesql.NewSelect("en\" FROM x --"),
+			expectedError: somesql.ErrInvalidLang,
+		},
+		{
+			name:          "fallback lang not allowed",
+			query:         somesql.NewSelect("en").Fallback("de"),
+			expectedError: somesql.ErrInvalidLang,
+		},
+		{
+			name:          "langs not allowed",
+			query:         somesql.NewSelect("en").Langs("en", "de"),
+			expectedError: somesql.ErrInvalidLang,
+		},
+		{
+			name:          "condition lang not allowed",
+			query:         somesql.NewSelect("en").Where(somesql.And("de", "data.title", "=", "x")),
+			expectedError: somesql.ErrInvalidLang,
+		},
+		{
+			name:  "lang of table without data",
+			query: somesql.NewSelect("de").From(somesql.TableSlugs).Where(somesql.And("de", "path", "=", "x")),
+		},
+		{
+			name:          "unsafe inner field",
+			query:         somesql.NewSelect("en").Fields("data.title'||version()||'"),
+			expectedError: somesql.ErrInvalidIdentifier,
+		},
+		{
+			name:          "unsafe condition field",
+			query:         somesql.NewSelect("en").Where(somesql.And("en", "data.a b", "=", "x")),
+			expectedError: somesql.ErrInvalidIdentifier,
+		},
+		{
+			name:          "unsafe order field",
+			query:         somesql.NewSelect("en").Order("title; DROP TABLE repo", true),
+			expectedError: somesql.ErrInvalidIdentifier,
+		},
+		{
+			name:          "unsafe alias",
+			query:         somesql.NewSelect("en").GroupBy("type").Count(`n" FROM repo --`),
+			expectedError: somesql.ErrInvalidIdentifier,
+		},
+		{
+			name:          "aggregate function not allowed",
+			query:         somesql.NewSelect("en").Aggregate("pg_sleep", "id", "n"),
+			expectedError: somesql.ErrInvalidFunction,
+		},
+		{
+			name:          "field function not allowed",
+			query:         somesql.NewSelect("en").Where(somesql.And("en", "id", "=", "x", "pg_sleep")),
+			expectedError: somesql.ErrInvalidFunction,
+		},
+		{
+			name:          "aggregate function within WHERE",
+			query:         somesql.NewSelect("en").Where(somesql.And("en", "id", "=", "1", "COUNT")),
+			expectedError: somesql.ErrInvalidFunction,
+		},
+		{
+			name:  "aggregate function within HAVING",
+			query: somesql.NewSelect("en").GroupBy("type").Having(somesql.AndGroup(somesql.And("en", "id", ">", 1, "count"))),
+		},
+		{
+			name:          "aggregate value function within HAVING",
+			query:         somesql.NewSelect("en").GroupBy("type").Having(somesql.And("en", "id", ">", 1, "LOWER", "COUNT")),
+			expectedError: somesql.ErrInvalidFunction,
+		},
+		{
+			name:          "value function not allowed",
+			query:         somesql.NewDelete("en").Where(somesql.AndIn("en", "id", []string{"x"}, "md5")),
+			expectedError: somesql.ErrInvalidFunction,
+		},
+		{
+			name:          "function on inner field IN",
+			query:         somesql.NewSelect("en").Where(somesql.AndIn("en", "data.tags", []string{"x"}, "LOWER")),
+			expectedError: somesql.ErrInvalidFunction,
+		},
+		{
+			name:          "operator not allowed",
+			query:         somesql.NewSelect("en").Where(somesql.And("en", "id", "= '' OR 1=1 OR id =", "x")),
+			expectedError: somesql.ErrInvalidOperator,
+		},
+		{
+			name:          "containment of a field which is not JSON",
+			query:         somesql.NewSelect("en").Where(somesql.AndContains("en", "type", "x")),
+			expectedError: somesql.ErrInvalidOperator,
+		},
+		{
+			name:          "regular expression on relations",
+			query:         somesql.NewSelect("en").Where(somesql.AndMatch("en", "relations.tags", "^a")),
+			expectedError: somesql.ErrInvalidOperator,
+		},
+		{
+			name:          "BETWEEN of a single value",
+			query:         somesql.NewSelect("en").Where(somesql.And("en", "data.views", somesql.OpBetween, 1)),
+			expectedError: somesql.ErrUnsupportedValue,
+		},
+		{
+			name:          "any key of no keys",
+			query:         somesql.NewSelect("en").Where(somesql.AndHasAnyKey("en", "data.seo")),
+			expectedError: somesql.ErrNoValues,
+		},
+		{
+			name:          "containment of a value which is not JSON",
+			query:         somesql.NewSelect("en").Where(somesql.AndContains("en", "data", func() {})),
+			expectedError: somesql.ErrInvalidJSON,
+		},
+		{
+			name:  "operators",
+			query: somesql.NewDelete("en").Where(somesql.AndIsNull("en", "owner_id")).Where(somesql.And("en", "data.title", "not ilike", "a%")).Where(somesql.AndHasKey("en", "relations.tags", "x")).Where(somesql.AndBetween("en", "created_at", "2020-01-01", "2021-01-01")),
+		},
+		{
+			name:          "unsafe statement alias",
+			query:         somesql.NewSelect("en").As(`a" --`),
+			expectedError: somesql.ErrInvalidIdentifier,
+		},
+		{
+			name:          "unsafe reference",
+			query:         somesql.NewSelect("en").As("a").Where(somesql.AndExists(somesql.NewSelectInner("en").Where(somesql.And("en", "owner_id", "=", somesql.Ref("a", "id'"))))),
+			expectedError: somesql.ErrInvalidIdentifier,
+		},
+		{
+			name:          "reference of a BETWEEN",
+			query:         somesql.NewSelect("en").Where(somesql.And("en", "id", somesql.OpBetween, somesql.Ref("a", "id"))),
+			expectedError: somesql.ErrUnsupportedValue,
+		},
+		{
+			name:          "invalid EXISTS query",
+			query:         somesql.NewSelect("en").Where(somesql.AndNotExists(somesql.NewSelectInner("de"))),
+			expectedError: somesql.ErrInvalidLang,
+		},
+		{
+			name:          "unsafe delete alias",
+			query:         somesql.NewDelete("en").As("a b"),
+			expectedError: somesql.ErrInvalidIdentifier,
+		},
+		{
+			name:          "unsafe inner field set",
+			query:         somesql.NewUpdate("en").Fields(somesql.NewFields().Set("data.x'", 1)),
+			expectedError: somesql.ErrInvalidIdentifier,
+		},
+		{
+			name:          "insert lang not allowed",
+			query:         somesql.NewInsert("de").Fields(somesql.NewFields().ID("a")),
+			expectedError: somesql.ErrInvalidLang,
+		},
+		{
+			name:          "unknown returning field",
+			query:         somesql.NewInsert("en").Fields(somesql.NewFields().ID("a")).Returning("id", "nope"),
+			expectedError: somesql.ErrUnknownField,
+		},
+	}
+
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := tt.query.Build()
+
+			if tt.expectedError == nil {
+				assert.Nil(t, err, fmt.Sprintf("%02d. %s :: unexpected error", i+1, tt.name))
+				return
+			}
+
+			assert.True(t, errors.Is(err, tt.expectedError), fmt.Sprintf("%02d. %s :: invalid error %v", i+1, tt.name, err))
+		})
+	}
+}
+
+func TestStatement_Quoting(t *testing.T) {
+	sql, _, err := somesql.NewSelect("en").Fields("id", "data.it's").Limit(0).Build()
+	assert.True(t, errors.Is(err, somesql.ErrInvalidIdentifier))
+	assert.Equal(t, `SELECT "id", json_build_object('it''s', "data_en"->'it''s') "data" FROM repo`, sql, "JSON keys are escaped")
+
+	sql, _, _ = somesql.NewSelect("en").Fields("data.x").GroupBy("type").Count(`n"`).Limit(0).Build()
+	assert.Equal(t, `SELECT "data_en"->>'x' "x", COUNT(*) "n""" FROM repo GROUP BY "type"`, sql, "aliases are escaped")
+
+	somesql.Languages = append(somesql.Languages, "mu")
+	defer func() { somesql.Languages = somesql.Languages[:len(somesql.Languages)-1] }()
+
+	_, _, err = somesql.NewSelect("mu").Fallback("en").Build()
+	assert.Nil(t, err, "languages are extended with Languages")
+}